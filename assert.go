@@ -0,0 +1,34 @@
+package gobdd
+
+import (
+	"reflect"
+)
+
+// Equal reports the failure of the step when expected and actual values aren't deeply equal
+func Equal(t StepTest, expected, actual interface{}) bool {
+	if reflect.DeepEqual(expected, actual) {
+		return true
+	}
+
+	t.Errorf("not equal:\nexpected: %#v\nactual  : %#v", expected, actual)
+
+	return false
+}
+
+// True reports the failure of the step when the condition is false
+func True(t StepTest, condition bool) bool {
+	if condition {
+		return true
+	}
+
+	t.Errorf("expected the condition to be true")
+
+	return false
+}
+
+// NoError stops the step when the error isn't nil
+func NoError(t StepTest, err error) {
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
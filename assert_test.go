@@ -0,0 +1,58 @@
+package gobdd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStepTest records messages of reported failures
+type fakeStepTest struct {
+	StepTest
+	errors []string
+	fatals []string
+}
+
+func (t *fakeStepTest) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeStepTest) Fatalf(format string, args ...interface{}) {
+	t.fatals = append(t.fatals, fmt.Sprintf(format, args...))
+}
+
+func TestEqual(t *testing.T) {
+	st := &fakeStepTest{}
+
+	require.True(t, Equal(st, []int{1, 2}, []int{1, 2}))
+	require.Empty(t, st.errors)
+
+	require.False(t, Equal(st, 3, 4))
+	require.False(t, Equal(st, "3", 3))
+	require.Equal(t, []string{
+		"not equal:\nexpected: 3\nactual  : 4",
+		"not equal:\nexpected: \"3\"\nactual  : 3",
+	}, st.errors)
+}
+
+func TestTrue(t *testing.T) {
+	st := &fakeStepTest{}
+
+	require.True(t, True(st, true))
+	require.Empty(t, st.errors)
+
+	require.False(t, True(st, false))
+	require.Equal(t, []string{"expected the condition to be true"}, st.errors)
+}
+
+func TestNoError(t *testing.T) {
+	st := &fakeStepTest{}
+
+	NoError(st, nil)
+	require.Empty(t, st.fatals)
+
+	NoError(st, errors.New("the connection was refused"))
+	require.Equal(t, []string{"unexpected error: the connection was refused"}, st.fatals)
+}
@@ -0,0 +1,71 @@
+package gobdd
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// WithChangedSince runs only features which files were modified since the git ref, e.g. "origin/main",
+// including uncommitted changes and new files which aren't ignored.
+// Changed files are listed by git in the current directory when the suite is created.
+// The option does nothing when git isn't available, the directory isn't a git repository or the ref doesn't exist
+func WithChangedSince(ref string) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		files, err := changedFilesSince(ref)
+		if err != nil {
+			return
+		}
+
+		WithChangedFiles(files...)(options)
+	}
+}
+
+// WithChangedFiles runs only features which paths are in the list of changed files, e.g. provided by the CI.
+// Paths are compared after cleaning them, so features are skipped when the list is empty
+func WithChangedFiles(paths ...string) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.onlyChanged = true
+		for _, path := range paths {
+			options.changedFiles = appendUnique(options.changedFiles, filepath.Clean(path))
+		}
+	}
+}
+
+// changedFilesSince lists files modified since the git ref and untracked files, relative to the current directory
+func changedFilesSince(ref string) ([]string, error) {
+	modified, err := exec.Command("git", "diff", "--name-only", "--relative", ref, "--").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	untracked, err := exec.Command("git", "ls-files", "--others", "--exclude-standard").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	for _, line := range strings.Split(string(modified)+"\n"+string(untracked), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+
+	return files, nil
+}
+
+// isChanged tells whether the feature should run according to WithChangedSince and WithChangedFiles
+func (s *Suite) isChanged(feature string) bool {
+	if !s.options.onlyChanged {
+		return true
+	}
+
+	feature = filepath.Clean(feature)
+	for _, path := range s.options.changedFiles {
+		if path == feature {
+			return true
+		}
+	}
+
+	return false
+}
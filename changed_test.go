@@ -0,0 +1,53 @@
+package gobdd
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithChangedFiles(t *testing.T) {
+	newSuite := func(executed *[]string, options ...func(*SuiteOptions)) *Suite {
+		suite := NewSuite(append(options,
+			WithOutput(io.Discard),
+			WithInlineFeature("features/unchanged.feature", `Feature: unchanged
+  Scenario: unchanged scenario
+    Given the step passes
+`),
+			WithInlineFeature("features/changed.feature", `Feature: changed
+  Scenario: changed scenario
+    Given the step passes
+`),
+		)...)
+		suite.AddStep(`the step passes`, func(ctx context.Context) {
+			*executed = append(*executed, ScenarioName(ctx))
+		})
+
+		return suite
+	}
+
+	executed := []string{}
+	require.NoError(t, newSuite(&executed, WithChangedFiles("go.mod", "./features/changed.feature")).Run())
+	require.Equal(t, []string{"changed scenario"}, executed)
+
+	executed = []string{}
+	require.NoError(t, newSuite(&executed, WithChangedFiles()).Run())
+	require.Empty(t, executed)
+}
+
+func TestWithChangedSinceUnknownRefRunsAllFeatures(t *testing.T) {
+	executed := []string{}
+	suite := NewSuite(WithOutput(io.Discard), WithChangedSince("gobdd-unknown-ref"),
+		WithInlineFeature("features/unchanged.feature", `Feature: unchanged
+  Scenario: unchanged scenario
+    Given the step passes
+`))
+	suite.AddStep(`the step passes`, func(ctx context.Context) {
+		executed = append(executed, ScenarioName(ctx))
+	})
+
+	require.NoError(t, suite.Run())
+	require.Equal(t, []string{"unchanged scenario"}, executed)
+}
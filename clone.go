@@ -0,0 +1,125 @@
+package gobdd
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Clone creates a new suite with copies of steps, parameter types and options of the suite.
+// Options passed to Clone are applied on top of the copied ones, e.g. to run other features with the same steps:
+// features configured by these options replace features of the suite instead of being added to them.
+// Steps and parameter types added to the clone don't affect the suite and the other way around
+func (s *Suite) Clone(optionClosures ...func(*SuiteOptions)) *Suite {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	options := s.options.clone()
+	features, featuresFS, warnings := options.features, options.featuresFS, options.featureWarnings
+	options.features, options.featureWarnings = nil, nil
+
+	for _, option := range optionClosures {
+		option(&options)
+	}
+
+	if options.features == nil {
+		options.features, options.featuresFS, options.featureWarnings = features, featuresFS, warnings
+	}
+
+	options.applyOverrides()
+
+	clone := &Suite{
+		steps:          make([]stepDef, 0, len(s.steps)),
+		options:        options,
+		parameterTypes: make(map[string][]string, len(s.parameterTypes)),
+		transforms:     make(map[string]transform, len(s.transforms)),
+	}
+
+	for from, to := range s.parameterTypes {
+		clone.parameterTypes[from] = append([]string{}, to...)
+	}
+
+	for group, f := range s.transforms {
+		clone.transforms[group] = f
+	}
+
+	clone.appendSteps(s.steps)
+
+	return clone
+}
+
+// Merge adds steps and parameter types of the other suite to the suite,
+// e.g. to compose suites from shared step libraries.
+// Options of the other suite are ignored.
+// It panics when a parameter type is defined with different regular expressions in both suites
+func (s *Suite) Merge(other *Suite) {
+	other.mu.RLock()
+	defer other.mu.RUnlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for from, to := range other.parameterTypes {
+		if existing, ok := s.parameterTypes[from]; ok && !reflect.DeepEqual(existing, to) {
+			panic(fmt.Sprintf("the parameter type %s is defined as %v in the suite but as %v in the merged suite",
+				from, existing, to))
+		}
+	}
+
+	for from, to := range other.parameterTypes {
+		if _, ok := s.parameterTypes[from]; !ok {
+			s.parameterTypes[from] = append([]string{}, to...)
+		}
+	}
+
+	for group, f := range other.transforms {
+		if _, ok := s.transforms[group]; !ok {
+			s.transforms[group] = f
+		}
+	}
+
+	s.appendSteps(other.steps)
+}
+
+// appendSteps adds copies of the step definitions which use transforms of the suite.
+// Variants of a step keep sharing a usage, but it isn't shared with the copied definitions
+func (s *Suite) appendSteps(steps []stepDef) {
+	usages := map[*stepUsage]*stepUsage{}
+
+	for _, def := range steps {
+		usage, ok := usages[def.usage]
+		if !ok {
+			usage = &stepUsage{expr: def.usage.expr}
+			usages[def.usage] = usage
+		}
+
+		s.steps = append(s.steps, s.newStepDef(def.expr, def.f, usage, def.keyword))
+	}
+}
+
+// clone returns a copy of the options which doesn't share slices and maps with them
+func (o SuiteOptions) clone() SuiteOptions {
+	c := o
+
+	c.features = append([]string{}, o.features...)
+	c.featureWarnings = append([]string{}, o.featureWarnings...)
+	c.inlineFeatures = append([]inlineFeature{}, o.inlineFeatures...)
+	c.excludePaths = append([]string{}, o.excludePaths...)
+	c.changedFiles = append([]string{}, o.changedFiles...)
+	c.ignoreTags = append([]string{}, o.ignoreTags...)
+	c.tags = append([]string{}, o.tags...)
+	c.beforeFeature = append([]func(ctx context.Context){}, o.beforeFeature...)
+	c.afterFeature = append([]func(ctx context.Context){}, o.afterFeature...)
+	c.beforeScenario = append([]scenarioHook{}, o.beforeScenario...)
+	c.afterScenario = append([]scenarioHook{}, o.afterScenario...)
+	c.beforeStep = append([]func(ctx context.Context) context.Context{}, o.beforeStep...)
+	c.afterStep = append([]func(ctx context.Context) context.Context{}, o.afterStep...)
+	c.listeners = append([]EventListener{}, o.listeners...)
+
+	c.lineFilters = make(map[string][]int64, len(o.lineFilters))
+	for path, lines := range o.lineFilters {
+		c.lineFilters[path] = append([]int64{}, lines...)
+	}
+
+	return c
+}
@@ -0,0 +1,68 @@
+package gobdd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClone(t *testing.T) {
+	base := NewSuite(WithFeaturesPath("features/report.feature"))
+	base.AddParameterTypes(`{color}`, []string{`(red|green)`})
+	base.AddStep(`the step passes`, pass)
+
+	clone := base.Clone(WithFeaturesPath("features/pending.feature"))
+	clone.AddParameterTypes(`{size}`, []string{`(small|big)`})
+	clone.AddStep(`the step is pending`, func(ctx context.Context) {
+		Pending()
+	})
+
+	require.Len(t, base.Steps(), 1)
+	require.Len(t, clone.Steps(), 2)
+	require.NotContains(t, base.parameterTypes, `{size}`)
+	require.Contains(t, clone.parameterTypes, `{color}`)
+	require.Equal(t, []string{"features/report.feature"}, base.options.features)
+	require.Equal(t, []string{"features/pending.feature"}, clone.options.features)
+
+	summary, err := clone.RunWithResult()
+	require.NoError(t, err)
+	require.Equal(t, ResultCounts{Pending: 1}, summary.Scenarios)
+	require.Empty(t, clone.UnusedSteps())
+	require.Equal(t, []string{"the step passes"}, base.UnusedSteps())
+}
+
+func TestMerge(t *testing.T) {
+	library := NewSuite()
+	library.AddParameterTypeTransform(`{upper}`, `[A-Z]+`, func(value string) (interface{}, error) {
+		return value + "!", nil
+	})
+
+	var shouted string
+	library.AddStep(`I shout {upper}`, func(ctx context.Context, value string) {
+		shouted = value
+	})
+
+	suite := NewSuite(WithFeaturesPath("features/report.feature"))
+	suite.AddStep(`the step passes`, pass)
+	suite.Merge(library)
+
+	require.Len(t, suite.Steps(), 2)
+
+	def, err := suite.findStepDef("I shout HELLO", "")
+	require.NoError(t, err)
+	def.run(context.Background(), newStepTest(t), nil, def.expr.FindSubmatch([]byte("I shout HELLO"))[1:])
+	require.Equal(t, "HELLO!", shouted)
+}
+
+func TestMergePanicsOnConflictingParameterTypes(t *testing.T) {
+	suite := NewSuite()
+	suite.AddParameterTypes(`{color}`, []string{`(red|green)`})
+
+	other := NewSuite()
+	other.AddParameterTypes(`{color}`, []string{`(blue)`})
+
+	require.Panics(t, func() {
+		suite.Merge(other)
+	})
+}
@@ -0,0 +1,155 @@
+package gobdd
+
+import (
+	"context"
+	"io"
+	"time"
+
+	msgs "github.com/cucumber/messages/go/v21"
+
+	"github.com/go-bdd/gobdd/models"
+)
+
+type scenarioKey struct{}
+
+type scenarioResultKey struct{}
+
+type stepKey struct{}
+
+type featureURIKey struct{}
+
+func withScenario(ctx context.Context, scenario *msgs.Scenario) context.Context {
+	return context.WithValue(ctx, scenarioKey{}, scenario)
+}
+
+func withScenarioResult(ctx context.Context, result *models.Scenario) context.Context {
+	return context.WithValue(ctx, scenarioResultKey{}, result)
+}
+
+func withStep(ctx context.Context, step *models.Step) context.Context {
+	return context.WithValue(ctx, stepKey{}, step)
+}
+
+func withFeatureURI(ctx context.Context, uri string) context.Context {
+	return context.WithValue(ctx, featureURIKey{}, uri)
+}
+
+func featureURIFromContext(ctx context.Context) string {
+	uri, _ := ctx.Value(featureURIKey{}).(string)
+
+	return uri
+}
+
+func scenarioFromContext(ctx context.Context) *msgs.Scenario {
+	scenario, _ := ctx.Value(scenarioKey{}).(*msgs.Scenario)
+
+	return scenario
+}
+
+// ScenarioName returns the name of the scenario which is currently executed.
+// It is available in scenario hooks, step hooks and steps
+func ScenarioName(ctx context.Context) string {
+	if scenario := scenarioFromContext(ctx); scenario != nil {
+		return scenario.Name
+	}
+
+	return ""
+}
+
+// ScenarioTags returns tags of the scenario which is currently executed
+func ScenarioTags(ctx context.Context) []string {
+	scenario := scenarioFromContext(ctx)
+	if scenario == nil {
+		return nil
+	}
+
+	tags := make([]string, 0, len(scenario.Tags))
+	for _, tag := range scenario.Tags {
+		tags = append(tags, tag.Name)
+	}
+
+	return tags
+}
+
+// ScenarioLocation returns the location of the scenario which is currently executed in its feature file
+func ScenarioLocation(ctx context.Context) *msgs.Location {
+	if scenario := scenarioFromContext(ctx); scenario != nil {
+		return scenario.Location
+	}
+
+	return nil
+}
+
+// ScenarioResult returns the result of the scenario which is currently executed, based on its steps executed so far.
+// In after scenario hooks it tells whether the scenario failed, e.g. to capture diagnostics
+func ScenarioResult(ctx context.Context) (models.Result, bool) {
+	result, ok := ctx.Value(scenarioResultKey{}).(*models.Scenario)
+	if !ok {
+		return models.Skipped, false
+	}
+
+	return result.Result(), true
+}
+
+// CurrentStep returns the step which is currently executed, with its text, keyword and location in the feature file.
+// It is available in steps and step hooks, e.g. to label artifacts captured after the step.
+// It returns nil outside of steps.
+// In after step hooks, Execution of the step holds its result and the error of a failed step,
+// e.g. to capture a screenshot only when the step failed
+func CurrentStep(ctx context.Context) *models.Step {
+	step, _ := ctx.Value(stepKey{}).(*models.Step)
+
+	return step
+}
+
+// Out returns the writer where steps should write their output instead of os.Stdout.
+// It can be configured with WithStepOutput
+func Out(ctx context.Context) io.Writer {
+	return models.RunContextFrom(ctx).Out
+}
+
+// ErrOut returns the writer where steps should write their errors instead of os.Stderr.
+// It can be configured with WithStepOutput
+func ErrOut(ctx context.Context) io.Writer {
+	return models.RunContextFrom(ctx).ErrOut
+}
+
+// valueKey wraps keys of values stored with SetValue, so they don't collide with other values of the context
+type valueKey struct {
+	key interface{}
+}
+
+// SetValue returns a copy of the context holding the value under the key. A step returning the context
+// passes the value to the following steps of the scenario. The context isn't modified,
+// so it's safe to use from steps of scenarios running in parallel. The key has to be comparable
+func SetValue(ctx context.Context, key, value interface{}) context.Context {
+	return context.WithValue(ctx, valueKey{key: key}, value)
+}
+
+// GetValue returns the value stored under the key with SetValue.
+// It returns false when there's no value under the key or the value isn't of the type T
+func GetValue[T any](ctx context.Context, key interface{}) (T, bool) {
+	value, ok := ctx.Value(valueKey{key: key}).(T)
+
+	return value, ok
+}
+
+// detachedContext holds values of a context returned by a step
+// but the deadline and the cancellation of the context the step was called with.
+// It prevents the timeout of a single step from cancelling the following steps
+type detachedContext struct {
+	context.Context
+	parent context.Context
+}
+
+func (c detachedContext) Deadline() (time.Time, bool) {
+	return c.parent.Deadline()
+}
+
+func (c detachedContext) Done() <-chan struct{} {
+	return c.parent.Done()
+}
+
+func (c detachedContext) Err() error {
+	return c.parent.Err()
+}
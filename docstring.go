@@ -0,0 +1,48 @@
+package gobdd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	msgs "github.com/cucumber/messages/go/v21"
+	"gopkg.in/yaml.v3"
+)
+
+// DocString holds the doc string attached to a step.
+//
+// A step function receives the doc string when its last parameter is a *gobdd.DocString:
+//
+//	func myStepFunction(ctx context.Context, doc *gobdd.DocString) {
+//	}
+//
+// If only the content is needed, the last parameter can be a string as well.
+type DocString struct {
+	Content   string
+	MediaType string
+}
+
+func newDocString(ds *msgs.DocString) *DocString {
+	if ds == nil {
+		return &DocString{}
+	}
+
+	return &DocString{
+		Content:   ds.Content,
+		MediaType: ds.MediaType,
+	}
+}
+
+// Decode unmarshals the content into v according to the media type of the doc string.
+// JSON (json, application/json) and YAML (yaml, yml, application/yaml, application/x-yaml, text/yaml)
+// are supported. The content is treated as JSON when the media type is empty
+func (d *DocString) Decode(v interface{}) error {
+	switch strings.ToLower(strings.TrimSpace(d.MediaType)) {
+	case "", "json", "application/json":
+		return json.Unmarshal([]byte(d.Content), v)
+	case "yaml", "yml", "application/yaml", "application/x-yaml", "text/yaml":
+		return yaml.Unmarshal([]byte(d.Content), v)
+	}
+
+	return fmt.Errorf("unsupported media type of the doc string: %s", d.MediaType)
+}
@@ -0,0 +1,34 @@
+package gobdd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type payload struct {
+	Name  string `json:"name" yaml:"name"`
+	Count int    `json:"count" yaml:"count"`
+}
+
+func TestDocStringDecode(t *testing.T) {
+	tests := map[string]*DocString{
+		"default media type": {Content: `{"name": "John", "count": 3}`},
+		"json":               {Content: `{"name": "John", "count": 3}`, MediaType: "application/json"},
+		"yaml":               {Content: "name: John\ncount: 3", MediaType: "yaml"},
+	}
+
+	for name, doc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var p payload
+			require.NoError(t, doc.Decode(&p))
+			require.Equal(t, payload{Name: "John", Count: 3}, p)
+		})
+	}
+}
+
+func TestDocStringDecodeUnsupportedMediaType(t *testing.T) {
+	doc := &DocString{Content: "<name>John</name>", MediaType: "xml"}
+
+	require.EqualError(t, doc.Decode(&payload{}), "unsupported media type of the doc string: xml")
+}
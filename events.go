@@ -0,0 +1,41 @@
+package gobdd
+
+import (
+	"github.com/go-bdd/gobdd/models"
+)
+
+// EventListener receives events of the run, e.g. to collect metrics or traces.
+// When scenarios run in parallel, the listener is called from multiple goroutines
+type EventListener interface {
+	// OnScenarioStart is called before the scenario and its hooks are executed
+	OnScenarioStart(scenario *models.Scenario)
+	// OnStepFinished is called when the step is finished or skipped
+	OnStepFinished(step *models.Step)
+	// OnScenarioFinished is called when all steps and after scenario hooks of the scenario are executed
+	OnScenarioFinished(scenario *models.Scenario)
+}
+
+// WithEventListener registers a listener of events of the run. Listeners are called in the order they were registered
+func WithEventListener(l EventListener) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.listeners = append(options.listeners, l)
+	}
+}
+
+func (s *Suite) scenarioStarted(scenario *models.Scenario) {
+	for _, l := range s.options.listeners {
+		l.OnScenarioStart(scenario)
+	}
+}
+
+func (s *Suite) stepFinished(step *models.Step) {
+	for _, l := range s.options.listeners {
+		l.OnStepFinished(step)
+	}
+}
+
+func (s *Suite) scenarioFinished(scenario *models.Scenario) {
+	for _, l := range s.options.listeners {
+		l.OnScenarioFinished(scenario)
+	}
+}
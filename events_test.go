@@ -0,0 +1,45 @@
+package gobdd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-bdd/gobdd/models"
+)
+
+type recordingListener struct {
+	events []string
+}
+
+func (l *recordingListener) OnScenarioStart(scenario *models.Scenario) {
+	l.events = append(l.events, "start "+scenario.Name)
+}
+
+func (l *recordingListener) OnStepFinished(step *models.Step) {
+	l.events = append(l.events, fmt.Sprintf("step %s%s %s", step.Keyword, step.Text, step.Execution.Result))
+}
+
+func (l *recordingListener) OnScenarioFinished(scenario *models.Scenario) {
+	l.events = append(l.events,
+		fmt.Sprintf("finish %s %s (%d steps)", scenario.Name, scenario.Result(), len(scenario.Steps)))
+}
+
+func TestWithEventListener(t *testing.T) {
+	first, second := &recordingListener{}, &recordingListener{}
+	suite := NewSuite(WithFeaturesPath("features/example.feature"), WithEventListener(first), WithEventListener(second))
+	suite.AddStep(`I add (\d+) and (\d+)`, add)
+	suite.AddStep(`the result should equal (\d+)`, check)
+
+	suite.RunWithT(t)
+
+	expected := []string{
+		"start add two digits",
+		"step When I add 1 and 2 passed",
+		"step Then the result should equal 3 passed",
+		"finish add two digits passed (2 steps)",
+	}
+	require.Equal(t, expected, first.events)
+	require.Equal(t, expected, second.events)
+}
@@ -0,0 +1,4 @@
+Feature: passing the context between steps
+  Scenario: the context returned by a step is passed to the next step
+    Given my name is John
+    Then my name should be John
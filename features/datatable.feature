@@ -0,0 +1,6 @@
+Feature: data tables
+  Scenario: passing a data table to the step
+    Given the following users exist:
+      | name | age |
+      | John | 42  |
+      | Jane | 37  |
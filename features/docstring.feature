@@ -0,0 +1,11 @@
+Feature: doc strings
+  Scenario: passing a doc string to the step
+    Given the following user:
+      """json
+      {"name": "John", "age": 42}
+      """
+  Scenario: passing a doc string content to the step
+    Given the following message:
+      """
+      hello world
+      """
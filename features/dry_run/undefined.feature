@@ -0,0 +1,4 @@
+Feature: undefined steps
+  Scenario: one of the steps is undefined
+    Given I have 5 cukes
+    When I cook 3 cukes
@@ -0,0 +1,4 @@
+Feature: valid steps
+  Scenario: all steps are defined
+    Given I have 5 cukes
+    When I eat 3 cukes
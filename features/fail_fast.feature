@@ -0,0 +1,7 @@
+Feature: failing scenarios
+  Scenario: the first scenario
+    When the first scenario fails
+    Then the first scenario continues
+  Scenario: the second scenario
+    When the second scenario fails
+    Then the second scenario continues
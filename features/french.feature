@@ -0,0 +1,18 @@
+# language: fr
+Fonctionnalité: opérations mathématiques
+  Contexte:
+    Soit I add 1 and 1
+
+  Scénario: additionner deux nombres
+    Quand I add 1 and 2
+    Et I add 2 and 3
+    Alors the result should equal 5
+    Mais the result should equal 5
+
+  Plan du scénario: additionner des nombres
+    Quand I add <a> and <b>
+    Alors the result should equal <c>
+
+    Exemples:
+      | a | b | c |
+      | 1 | 2 | 3 |
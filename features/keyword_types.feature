@@ -0,0 +1,7 @@
+Feature: keyword types
+  Scenario: steps with conjunctions
+    Given the step passes
+    And the step passes
+    When the step passes
+    Then the step passes
+    But the step passes
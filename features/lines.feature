@@ -0,0 +1,13 @@
+Feature: line filters
+  Scenario: the first scenario
+    When the "first" scenario runs
+
+  Scenario: the second scenario
+    When the "second" scenario runs
+
+  Scenario Outline: the outline
+    When the "<name>" scenario runs
+    Examples:
+      | name   |
+      | third  |
+      | fourth |
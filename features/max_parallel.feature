@@ -0,0 +1,11 @@
+Feature: limiting parallel scenarios
+  Scenario: the first scenario
+    When I take some time
+  Scenario: the second scenario
+    When I take some time
+  Scenario: the third scenario
+    When I take some time
+  Scenario: the fourth scenario
+    When I take some time
+  Scenario: the fifth scenario
+    When I take some time
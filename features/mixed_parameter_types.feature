@@ -0,0 +1,3 @@
+Feature: mixed parameter types
+  Scenario: integer and word
+    Given I have 3 apples
@@ -0,0 +1,5 @@
+Feature: named capturing groups
+  Scenario: filling a struct
+    When John has 3 apples
+  Scenario: filling a map
+    When Jane has 5 pears
@@ -0,0 +1,4 @@
+Feature: nested middle
+  Scenario: middle
+    When I add 2 and 2
+    Then the result should equal 4
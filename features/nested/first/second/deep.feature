@@ -0,0 +1,4 @@
+Feature: nested deep
+  Scenario: deep
+    When I add 3 and 2
+    Then the result should equal 5
@@ -0,0 +1,4 @@
+Feature: nested top
+  Scenario: top
+    When I add 1 and 2
+    Then the result should equal 3
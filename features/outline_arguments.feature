@@ -0,0 +1,13 @@
+Feature: outline with step arguments
+  Scenario Outline: greeting users
+    Given the message:
+      """
+      Hello <name>!
+      """
+    And the user:
+      | name   | age   |
+      | <name> | <age> |
+    Examples:
+      | name | age |
+      | John | 42  |
+      | Jane | 37  |
@@ -0,0 +1,11 @@
+Feature: scenario outline with background
+  Background:
+    Given the counter is set to 10
+
+  Scenario Outline: adding to the counter
+    When I add <number> to the counter
+    Then the counter equals <total>
+    Examples:
+      | number | total |
+      | 1      | 11    |
+      | 5      | 15    |
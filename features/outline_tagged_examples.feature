@@ -0,0 +1,15 @@
+Feature: scenario outline with tagged examples
+  Scenario Outline: checking numbers
+    When the number <number> is checked
+
+    @positive
+    Examples: positive numbers
+      | number |
+      | 1      |
+      | 2      |
+
+    @negative
+    Examples: negative numbers
+      | number |
+      | -1     |
+      | -2     |
@@ -0,0 +1,5 @@
+Feature: running scenarios in parallel
+  Scenario: the first scenario
+    When I wait for the other scenario
+  Scenario: the second scenario
+    When I wait for the other scenario
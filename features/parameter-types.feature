@@ -2,6 +2,12 @@ Feature: parameter types
   Scenario: add two digits
     When I add 1 and 2
     Then the result should equal 3
+  Scenario: add multi-digit integers
+    When I add 12 and 345
+    Then the result should equal 357
+  Scenario: add negative integers
+    When I add -12 and 5
+    Then the result should equal -7
   Scenario: simple word
     When I use word pizza
   Scenario: simple text with double quotes
@@ -10,4 +16,8 @@ Feature: parameter types
     When I use text 'I like pizza'
   Scenario: add two floats
     When I add floats 1 and 2
-    Then the result should equal float 3
\ No newline at end of file
+    Then the result should equal float 3
+  Scenario: duration
+    When I wait 1500ms
+  Scenario: anything
+    Then the log says error: disk full (code 5)
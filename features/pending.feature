@@ -0,0 +1,5 @@
+Feature: pending steps
+  Scenario: the pending scenario
+    When the step passes
+    And the step is pending
+    Then the step passes
@@ -0,0 +1,10 @@
+Feature: progress
+  Scenario: the passing scenario
+    When the step passes
+    Then the step passes
+  Scenario: the failing scenario
+    When the step fails
+    Then the step passes
+  Scenario: the undefined scenario
+    When the step is undefined
+    Then the step passes
@@ -0,0 +1,8 @@
+Feature: reporting results
+  Scenario: the passing scenario
+    When the step passes
+    Then the step passes
+  Scenario: the failing scenario
+    When the step passes
+    Then the step fails
+    And the step passes
@@ -0,0 +1,4 @@
+Feature: retrying steps
+  Scenario: flaky step
+    When the step fails twice
+    Then the step passes
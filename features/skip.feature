@@ -0,0 +1,14 @@
+Feature: skipping scenarios
+  Background:
+    Given the step passes
+
+  Scenario: the passing scenario
+    When the step passes
+
+  @skip
+  Scenario: the skipped scenario
+    When the step fails
+
+  @wip
+  Scenario: the work in progress scenario
+    When the step passes
@@ -0,0 +1,8 @@
+Feature: slow scenarios
+  Scenario: the fast scenario
+    When I wait 10ms
+  Scenario: the slowest scenario
+    When I wait 60ms
+    And I wait 20ms
+  Scenario: the slow scenario
+    When I wait 40ms
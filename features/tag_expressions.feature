@@ -0,0 +1,12 @@
+Feature: tag expressions
+  @smoke @fast
+  Scenario: smoke and fast
+    Then the "smoke and fast" scenario runs
+  @smoke @wip
+  Scenario: smoke and wip
+    Then the "smoke and wip" scenario runs
+  @slow
+  Scenario: slow
+    Then the "slow" scenario runs
+  Scenario: untagged
+    Then the "untagged" scenario runs
@@ -0,0 +1,3 @@
+Feature: parameter type transforms
+  Scenario: converting dates
+    When the release date is 2006-01-02
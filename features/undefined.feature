@@ -0,0 +1,7 @@
+Feature: undefined steps
+  Scenario: eating cukes
+    Given I have 5 cukes
+    When I eat 3 cukes
+  Scenario: eating more cukes
+    Given I have 10 cukes
+    When I eat 7 cukes
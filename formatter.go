@@ -0,0 +1,238 @@
+package gobdd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-bdd/gobdd/models"
+)
+
+// Formatter reports the progress of the suite while it runs
+type Formatter interface {
+	// Feature is called when the feature starts running
+	Feature(feature *models.Feature)
+	// Scenario is called when the scenario starts running
+	Scenario(scenario *models.Scenario)
+	// Step is called when the step is finished or skipped
+	Step(step *models.Step, result models.Result, duration time.Duration)
+	// Summary is called at the end of the run with all executed features
+	Summary(features []*models.Feature)
+}
+
+const (
+	colorReset  = "\033[0m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+)
+
+// PrettyFormatter prints every feature, scenario and step colored by its result.
+// Locations and errors are printed for failed steps
+type PrettyFormatter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	colors bool
+	uri    string
+}
+
+// NewPrettyFormatter creates a formatter writing to w. ANSI colors are used only when w is a terminal
+func NewPrettyFormatter(w io.Writer) *PrettyFormatter {
+	return &PrettyFormatter{w: w, colors: isTerminal(w)}
+}
+
+func (f *PrettyFormatter) Feature(feature *models.Feature) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.uri = feature.Uri
+	fmt.Fprintf(f.w, "%s: %s\n", feature.Keyword, feature.Name)
+}
+
+func (f *PrettyFormatter) Scenario(scenario *models.Scenario) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	fmt.Fprintf(f.w, "\n  %s: %s\n", scenario.Keyword, scenario.Name)
+}
+
+func (f *PrettyFormatter) Step(step *models.Step, result models.Result, duration time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	fmt.Fprintf(f.w, "    %s\n", f.colorize(result, step.Keyword+step.Text))
+
+	if result == models.Failed || result == models.Undefined {
+		fmt.Fprintf(f.w, "      %s:%d: %s\n",
+			f.uri, line(step.Location), f.colorize(result, errorMessage(step.Execution.Err)))
+	}
+
+	if stack := strings.TrimSpace(string(step.Execution.Stack)); stack != "" {
+		fmt.Fprintf(f.w, "        %s\n", strings.ReplaceAll(stack, "\n", "\n        "))
+	}
+}
+
+func (f *PrettyFormatter) Summary(features []*models.Feature) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	scenarios, steps := countResults(features)
+
+	fmt.Fprintf(f.w, "\n%s\n%s\n",
+		summaryLine("scenarios", scenarios, f.colorize), summaryLine("steps", steps, f.colorize))
+}
+
+func (f *PrettyFormatter) colorize(result models.Result, text string) string {
+	if !f.colors {
+		return text
+	}
+
+	color := colorYellow
+	switch result {
+	case models.Passed:
+		color = colorGreen
+	case models.Failed:
+		color = colorRed
+	}
+
+	return color + text + colorReset
+}
+
+// ProgressFormatter prints a single character for every step: '.' when passed, 'F' when failed,
+// '-' when skipped, 'U' when undefined and 'P' when pending, followed by a summary at the end of the run
+type ProgressFormatter struct {
+	mu    sync.Mutex
+	w     io.Writer
+	start time.Time
+}
+
+// NewProgressFormatter creates a formatter writing to w
+func NewProgressFormatter(w io.Writer) *ProgressFormatter {
+	return &ProgressFormatter{w: w}
+}
+
+func (f *ProgressFormatter) Feature(*models.Feature) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.start.IsZero() {
+		f.start = time.Now()
+	}
+}
+
+func (f *ProgressFormatter) Scenario(*models.Scenario) {}
+
+func (f *ProgressFormatter) Step(_ *models.Step, result models.Result, _ time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	progress := "?"
+	switch result {
+	case models.Passed:
+		progress = "."
+	case models.Failed:
+		progress = "F"
+	case models.Skipped:
+		progress = "-"
+	case models.Undefined:
+		progress = "U"
+	case models.Pending:
+		progress = "P"
+	}
+
+	fmt.Fprint(f.w, progress)
+}
+
+func (f *ProgressFormatter) Summary(features []*models.Feature) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var elapsed time.Duration
+	if !f.start.IsZero() {
+		elapsed = time.Since(f.start)
+	}
+
+	scenarios, steps := countResults(features)
+
+	fmt.Fprintf(f.w, "\n\n%s\n%s\n%s\n",
+		summaryLine("scenarios", scenarios, plain), summaryLine("steps", steps, plain), elapsed)
+}
+
+// writeSummary writes how many scenarios and steps there were for every result
+func writeSummary(w io.Writer, features []*models.Feature) {
+	scenarios, steps := countResults(features)
+
+	fmt.Fprintf(w, "%s\n%s\n", summaryLine("scenarios", scenarios, plain), summaryLine("steps", steps, plain))
+}
+
+// writeSlowest writes durations and locations of the slowest scenarios
+func writeSlowest(w io.Writer, slowest []Timing) {
+	fmt.Fprintln(w, "\nslowest scenarios:")
+
+	for _, timing := range slowest {
+		fmt.Fprintf(w, "  %s %s:%d %s\n", timing.Duration.Round(time.Millisecond), timing.Uri, timing.Line, timing.Name)
+	}
+}
+
+// plain leaves the text of summary lines without colors
+func plain(_ models.Result, text string) string {
+	return text
+}
+
+// summaryLine describes how many scenarios or steps there were for every result
+func summaryLine(name string, counts map[models.Result]int, colorize func(models.Result, string) string) string {
+	total := 0
+	parts := []string{}
+
+	results := []models.Result{models.Passed, models.Failed, models.Undefined, models.Pending, models.Skipped}
+	for _, result := range results {
+		if counts[result] == 0 {
+			continue
+		}
+
+		total += counts[result]
+		parts = append(parts, colorize(result, fmt.Sprintf("%d %s", counts[result], result)))
+	}
+
+	if len(parts) == 0 {
+		return fmt.Sprintf("0 %s", name)
+	}
+
+	return fmt.Sprintf("%d %s (%s)", total, name, strings.Join(parts, ", "))
+}
+
+// countResults counts executed scenarios and steps by their results
+func countResults(features []*models.Feature) (scenarios, steps map[models.Result]int) {
+	scenarios = map[models.Result]int{}
+	steps = map[models.Result]int{}
+
+	for _, feature := range features {
+		for _, scenario := range feature.Scenarios {
+			scenarios[scenario.Result()]++
+
+			for _, step := range scenario.Steps {
+				steps[step.Execution.Result]++
+			}
+		}
+	}
+
+	return scenarios, steps
+}
+
+// isTerminal tells whether w is a character device, like a terminal
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,107 @@
+package gobdd
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrettyFormatter(t *testing.T) {
+	output := &bytes.Buffer{}
+	suite := NewSuite(WithFeaturesPath("features/report.feature"), WithFormatter(NewPrettyFormatter(output)))
+	suite.AddStep(`the step passes`, pass)
+	suite.AddStep(`the step fails`, failure)
+
+	require.NoError(t, suite.Run())
+
+	require.Equal(t, strings.Join([]string{
+		"Feature: reporting results",
+		"",
+		"  Scenario: the passing scenario",
+		"    When the step passes",
+		"    Then the step passes",
+		"",
+		"  Scenario: the failing scenario",
+		"    When the step passes",
+		"    Then the step fails",
+		"      features/report.feature:7: the step failed",
+		"    And the step passes",
+		"",
+		"2 scenarios (1 passed, 1 failed)",
+		"5 steps (3 passed, 1 failed, 1 skipped)",
+		"",
+	}, "\n"), output.String())
+}
+
+func TestPrettyFormatterColors(t *testing.T) {
+	output := &bytes.Buffer{}
+	formatter := NewPrettyFormatter(output)
+	require.False(t, formatter.colors)
+
+	formatter.colors = true
+	suite := NewSuite(WithFeaturesPath("features/report.feature"), WithFormatter(formatter))
+	suite.AddStep(`the step passes`, pass)
+	suite.AddStep(`the step fails`, failure)
+
+	require.NoError(t, suite.Run())
+
+	require.Contains(t, output.String(), "    \033[32mWhen the step passes\033[0m\n")
+	require.Contains(t, output.String(), "    \033[31mThen the step fails\033[0m\n")
+	require.Contains(t, output.String(), "    \033[33mAnd the step passes\033[0m\n")
+}
+
+func TestProgressFormatter(t *testing.T) {
+	output := &bytes.Buffer{}
+	suite := NewSuite(
+		WithFeaturesPath("features/progress.feature"),
+		WithFormatter(NewProgressFormatter(output)),
+		WithUndefinedStepSnippets(io.Discard),
+	)
+	suite.AddStep(`the step passes`, pass)
+	suite.AddStep(`the step fails`, failure)
+
+	require.NoError(t, suite.Run())
+
+	lines := strings.Split(output.String(), "\n")
+	require.Len(t, lines, 6)
+	require.Equal(t, "..F-U-", lines[0])
+	require.Empty(t, lines[1])
+	require.Equal(t, "3 scenarios (1 passed, 1 failed, 1 undefined)", lines[2])
+	require.Equal(t, "6 steps (2 passed, 1 failed, 1 undefined, 2 skipped)", lines[3])
+	_, err := time.ParseDuration(lines[4])
+	require.NoError(t, err)
+	require.Empty(t, lines[5])
+}
+
+func TestPrettyFormatterLocalizedKeywords(t *testing.T) {
+	output := &bytes.Buffer{}
+	suite := NewSuite(WithFeaturesPath("features/french.feature"), WithFormatter(NewPrettyFormatter(output)))
+	suite.AddStep(`I add {int} and {int}`, add)
+	suite.AddStep(`the result should equal {int}`, check)
+
+	require.NoError(t, suite.Run())
+
+	require.Equal(t, strings.Join([]string{
+		"Fonctionnalité: opérations mathématiques",
+		"",
+		"  Scénario: additionner deux nombres",
+		"    Soit I add 1 and 1",
+		"    Quand I add 1 and 2",
+		"    Et I add 2 and 3",
+		"    Alors the result should equal 5",
+		"    Mais the result should equal 5",
+		"",
+		"  Plan du scénario: additionner des nombres example #1 (1, 2, 3)",
+		"    Soit I add 1 and 1",
+		"    Quand I add 1 and 2",
+		"    Alors the result should equal 3",
+		"",
+		"2 scenarios (2 passed)",
+		"8 steps (8 passed)",
+		"",
+	}, "\n"), output.String())
+}
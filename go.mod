@@ -1,13 +1,24 @@
 module github.com/go-bdd/gobdd
 
-go 1.16
+go 1.18
 
 require (
 	github.com/cucumber/gherkin/go/v26 v26.0.2
 	github.com/cucumber/messages/go/v21 v21.0.1
 	github.com/go-bdd/assert v0.0.0-20190820124234-20d47a68475d
-	github.com/gofrs/uuid v4.3.1+incompatible // indirect
 	github.com/onsi/ginkgo/v2 v2.6.1
 	github.com/onsi/gomega v1.24.1
 	github.com/stretchr/testify v1.8.1
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/go-logr/logr v1.2.3 // indirect
+	github.com/gofrs/uuid v4.3.1+incompatible // indirect
+	github.com/google/go-cmp v0.5.9 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/net v0.3.0 // indirect
+	golang.org/x/sys v0.3.0 // indirect
+	golang.org/x/text v0.5.0 // indirect
 )
@@ -5,41 +5,166 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
+	"net/mail"
+	"net/url"
 	"os"
+	"path"
+	"path/filepath"
 	"reflect"
 	"regexp"
+	"runtime"
+	"runtime/debug"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
 
 	gherkin "github.com/cucumber/gherkin/go/v26"
 	msgs "github.com/cucumber/messages/go/v21"
+
+	"github.com/go-bdd/gobdd/models"
 )
 
 // Suite holds all the information about the suite (options, steps to execute etc)
 type Suite struct {
+	mu             sync.RWMutex
 	steps          []stepDef
 	options        SuiteOptions
 	parameterTypes map[string][]string
+	transforms     map[string]transform
+	undefinedSteps []*msgs.Step
+	invalidSteps   []string
+	stopped        int32
+	deadline       context.Context
+	results        []*models.Feature
+	messages       *messagesEmitter
+	teamcity       *teamCityOutput
 }
 
 // SuiteOptions holds all the information about how the suite or features/steps should be configured
 type SuiteOptions struct {
-	features       []string
-	ignoreTags     []string
-	tags           []string
-	beforeScenario []func(ctx context.Context)
-	afterScenario  []func(ctx context.Context)
-	beforeStep     []func(ctx context.Context)
-	afterStep      []func(ctx context.Context)
-	runInParallel  bool
+	features        []string
+	featuresFS      fs.FS
+	featureWarnings []string
+	inlineFeatures  []inlineFeature
+	excludePaths    []string
+	changedFiles    []string
+	onlyChanged     bool
+	ctx             context.Context
+	ignoreTags      []string
+	tags            []string
+	tagExpression   tagExpression
+	lineFilters     map[string][]int64
+	nameFilter      *regexp.Regexp
+	beforeFeature   []func(ctx context.Context)
+	afterFeature    []func(ctx context.Context)
+	beforeScenario  []scenarioHook
+	afterScenario   []scenarioHook
+	beforeStep      []func(ctx context.Context) context.Context
+	afterStep       []func(ctx context.Context) context.Context
+	runInParallel   bool
+	maxParallel     int
+	dryRun          bool
+	failFast        bool
+	requireFeatures bool
+	normalizeSpace  bool
+	ignoreCase      bool
+	listSeparator   string
+	skipValidation  bool
+	wipStrict       bool
+	strict          bool
+	stepTimeout     time.Duration
+	scenarioTimeout time.Duration
+	suiteTimeout    time.Duration
+	stepRetries     int
+	scenarioRetries int
+	stepBackoff     time.Duration
+
+	undefinedSnippets io.Writer
+	jsonReport        io.Writer
+	junitReport       io.Writer
+	htmlReport        io.Writer
+	rerunReport       string
+	teamcityOutput    io.Writer
+	messagesOutput    io.Writer
+	formatter         Formatter
+	listeners         []EventListener
+	stepResolver      StepResolver
+	stepOutput        *models.RunContext
+	output            io.Writer
+	flagOverrides     bool
+	tagsEnv           string
+	slowest           int
 }
 
-// WithFeaturesFS configures a filesystem and a path (glob pattern) where features can be found.
-func WithFeaturesFS(path string) func(*SuiteOptions) {
+// WithFeaturesFS configures a filesystem and paths (glob patterns) where features can be found.
+// Feature files are read from the filesystem, which can be an embed.FS.
+// Using the option again with the same filesystem adds paths, while another filesystem replaces them.
+// A feature matching more than one pattern runs once
+func WithFeaturesFS(fsys fs.FS, paths ...string) func(*SuiteOptions) {
 	return func(options *SuiteOptions) {
-		features, _ := fs.Glob(os.DirFS("."), path)
+		features, warnings := []string{}, []string{}
+		if sameFS(options.featuresFS, fsys) {
+			features = append(features, options.features...)
+			warnings = append(warnings, options.featureWarnings...)
+		}
+
+		for _, path := range paths {
+			matches, err := fs.Glob(fsys, path)
+			if warning := featuresPatternWarning(path, matches, err); warning != "" {
+				warnings = append(warnings, warning)
+			}
+
+			features = appendUnique(features, matches...)
+		}
+
 		options.features = features
+		options.featureWarnings = warnings
+		options.featuresFS = fsys
+	}
+}
+
+// sameFS tells whether both filesystems are the same one. Filesystems which aren't comparable, like fstest.MapFS,
+// are the same when they point to the same data
+func sameFS(a, b fs.FS) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Type() != vb.Type() {
+		return false
+	}
+
+	if va.Type().Comparable() {
+		return a == b
+	}
+
+	switch va.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Func:
+		return va.Pointer() == vb.Pointer()
+	default:
+		return false
+	}
+}
+
+// inlineFeature is a feature which content is passed to the suite instead of being read from a file
+type inlineFeature struct {
+	name    string
+	content string
+}
+
+// WithInlineFeature adds a feature with the content, e.g. to test steps without creating feature files.
+// The name is used instead of the path of the feature file in reports and line filters.
+// Inline features run after features found in paths and replace features with the same path
+func WithInlineFeature(name, content string) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.inlineFeatures = append(options.inlineFeatures, inlineFeature{name: name, content: content})
 	}
 }
 
@@ -47,27 +172,373 @@ func WithFeaturesFS(path string) func(*SuiteOptions) {
 func NewSuiteOptions() SuiteOptions {
 	return SuiteOptions{
 		//featureSource:  pathFeatureSource("features/*.feature"),
+		ctx:            context.Background(),
 		ignoreTags:     []string{},
 		tags:           []string{},
-		beforeScenario: []func(ctx context.Context){},
-		afterScenario:  []func(ctx context.Context){},
-		beforeStep:     []func(ctx context.Context){},
-		afterStep:      []func(ctx context.Context){},
+		beforeFeature:  []func(ctx context.Context){},
+		afterFeature:   []func(ctx context.Context){},
+		beforeScenario: []scenarioHook{},
+		afterScenario:  []scenarioHook{},
+		beforeStep:     []func(ctx context.Context) context.Context{},
+		afterStep:      []func(ctx context.Context) context.Context{},
+		lineFilters:    map[string][]int64{},
+		listSeparator:  ",",
+		output:         os.Stdout,
+	}
+}
+
+// WithContext configures the context which contexts of features and scenarios are derived from.
+// Its values are available in hooks and steps
+func WithContext(ctx context.Context) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.ctx = ctx
+	}
+}
+
+// WithOutput configures the writer where the suite reports failures, warnings and the summary of the run
+// when it isn't run with testing.T, os.Stdout by default
+func WithOutput(w io.Writer) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.output = w
 	}
 }
 
-// RunInParallel runs tests in parallel
+// WithSlowestReport adds the n slowest scenarios to the summary of the run written to the output of the suite.
+// Durations of all features and scenarios are available in the Summary returned by RunWithResult
+func WithSlowestReport(n int) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.slowest = n
+	}
+}
+
+// WithStepOutput configures writers returned by Out and ErrOut in steps and hooks of scenarios.
+// By default steps write to os.Stdout and os.Stderr
+func WithStepOutput(out, errOut io.Writer) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.stepOutput = &models.RunContext{Out: out, ErrOut: errOut}
+	}
+}
+
+// RunInParallel runs scenarios of a feature in parallel, every scenario in its own goroutine
 func RunInParallel() func(*SuiteOptions) {
 	return func(options *SuiteOptions) {
 		options.runInParallel = true
 	}
 }
 
-// WithFeaturesPath configures a pattern (regexp) where feature can be found.
-// The default value is "features/*.feature"
-func WithFeaturesPath(path []string) func(*SuiteOptions) {
+// WithMaxParallel limits how many scenarios can run at the same time when running in parallel.
+// When n is lower than 1, the limit is set to runtime.GOMAXPROCS(0)
+func WithMaxParallel(n int) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		if n <= 0 {
+			n = runtime.GOMAXPROCS(0)
+		}
+
+		options.maxParallel = n
+	}
+}
+
+// WithLineFilter runs only the scenario spanning the line of the feature file at the path.
+// When the line points to a row of examples of a scenario outline, only the row is executed.
+// When any line filter is configured, features without a line filter are not executed
+func WithLineFilter(path string, line int) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		path = filepath.Clean(path)
+		options.lineFilters[path] = append(options.lineFilters[path], int64(line))
+	}
+}
+
+// WithNameFilter runs only scenarios which names match the regular expression.
+// Scenario outlines are matched by their names, without values from examples.
+// The filter is combined with tag filters, so a scenario has to pass both of them.
+// The regular expression should compile, otherwise it panics
+func WithNameFilter(pattern string) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		filter, err := regexp.Compile(pattern)
+		if err != nil {
+			panic(fmt.Sprintf("the name filter %s doesn't compile: %s", pattern, err))
+		}
+
+		options.nameFilter = filter
+	}
+}
+
+// WithFailFast stops running further scenarios and features after the first failed scenario.
+// Regardless of this option, steps following a failed step in the same scenario are never executed.
+// When it's combined with WithContinueOnFailure, the option passed last wins
+func WithFailFast() func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.failFast = true
+	}
+}
+
+// WithContinueOnFailure keeps running remaining scenarios and features after a scenario fails, which is the default.
+// It overrides WithFailFast passed before it, e.g. in options shared by several suites or in Clone
+func WithContinueOnFailure() func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.failFast = false
+	}
+}
+
+// WithRequireFeatures makes the suite fail with an error when no feature files were found
+func WithRequireFeatures() func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.requireFeatures = true
+	}
+}
+
+// WithNormalizeWhitespace trims the text of every step and collapses runs of whitespace into a single space
+// before the step is matched with step definitions. Text in quotes is left untouched
+func WithNormalizeWhitespace() func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.normalizeSpace = true
+	}
+}
+
+// WithListSeparator configures the separator of elements of captured values passed to slice arguments
+// of step functions, e.g. "|" for []string arguments filled with "a|b|c". The default separator is a comma.
+// It applies to steps added after the suite is created and panics when the separator is empty
+func WithListSeparator(sep string) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		if sep == "" {
+			panic("the list separator cannot be empty")
+		}
+
+		options.listSeparator = sep
+	}
+}
+
+// WithoutParameterValidation passes values captured by {email} and {url} to step functions without checking
+// that they're valid email addresses and URLs
+func WithoutParameterValidation() func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.skipValidation = true
+	}
+}
+
+// WithCaseInsensitiveSteps makes expressions of steps match the text of steps regardless of the case.
+// It applies to steps added after the suite is created
+func WithCaseInsensitiveSteps() func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.ignoreCase = true
+	}
+}
+
+// WithWIPStrict runs scenarios tagged with @wip (work in progress) like any other scenario.
+// By default, they're skipped like the ones tagged with @skip
+func WithWIPStrict() func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.wipStrict = true
+	}
+}
+
+// WithStepTimeout fails steps which don't return within d.
+// The step function receives a context with the deadline, so it can stop its work when the context is done.
+// Step functions which ignore the context keep running in the background after the timeout
+func WithStepTimeout(d time.Duration) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.stepTimeout = d
+	}
+}
+
+// WithScenarioTimeout fails scenarios which don't finish within d. The remaining steps of the scenario are skipped.
+// Steps receive a context with the deadline, while after-scenario hooks are called even when the scenario timed out
+func WithScenarioTimeout(d time.Duration) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.scenarioTimeout = d
+	}
+}
+
+// WithSuiteTimeout limits how long the whole run can take. Once d elapses, scenarios which haven't started yet
+// are reported as skipped, without running their steps and hooks, and Run returns an error.
+// Scenarios which are already running aren't interrupted
+func WithSuiteTimeout(d time.Duration) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.suiteTimeout = d
+	}
+}
+
+// WithStepRetry retries a failed step up to attempts times, waiting for backoff before every retry.
+// The step is reported as failed only when the last retry fails.
+// Before and after step hooks are called once for the step, not for every retry
+func WithStepRetry(attempts int, backoff time.Duration) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.stepRetries = attempts
+		options.stepBackoff = backoff
+	}
+}
+
+// WithScenarioRetry re-runs a failed scenario from the start, including its background and hooks, up to attempts times.
+// Only the last attempt is recorded in results and reports, together with the number of attempts,
+// and failures of previous attempts are only logged
+func WithScenarioRetry(attempts int) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.scenarioRetries = attempts
+	}
+}
+
+// WithDryRun configures the suite to only check if every step has a matching step definition
+// which accepts the step's arguments. Neither step functions nor hooks are executed.
+func WithDryRun() func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.dryRun = true
+	}
+}
+
+// WithStrict treats pending steps as failures, like undefined steps.
+// Their scenarios fail and the summary of the run isn't successful
+func WithStrict() func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.strict = true
+	}
+}
+
+// WithJSONReport configures a writer where the JSON report of executed features, scenarios and steps
+// is written at the end of a run
+func WithJSONReport(w io.Writer) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.jsonReport = w
+	}
+}
+
+// WithJUnitReport configures a writer where the JUnit XML report of executed scenarios is written at the end of a run.
+// Every feature is reported as a test suite and every scenario as a test case
+func WithJUnitReport(w io.Writer) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.junitReport = w
+	}
+}
+
+// WithHTMLReport configures a writer where a self-contained HTML report of executed features, scenarios and steps
+// is written at the end of a run
+func WithHTMLReport(w io.Writer) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.htmlReport = w
+	}
+}
+
+// WithTeamCityOutput configures a writer where TeamCity service messages are written while the suite runs.
+// Every feature is reported as a test suite and every scenario as a test
+func WithTeamCityOutput(w io.Writer) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.teamcityOutput = w
+	}
+}
+
+// WithMessagesOutput configures a writer where Cucumber messages are written as newline-delimited JSON
+// while the suite runs.
+// The messages of a scenario are written once the scenario is finished
+func WithMessagesOutput(w io.Writer) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.messagesOutput = w
+	}
+}
+
+// WithFormatter configures a formatter which reports the progress of the suite while it runs
+func WithFormatter(f Formatter) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.formatter = f
+	}
+}
+
+// WithUndefinedStepSnippets configures a writer where snippets of undefined steps are written at the end of a run
+func WithUndefinedStepSnippets(w io.Writer) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.undefinedSnippets = w
+	}
+}
+
+// WithFeaturesPath configures paths (glob patterns) where features can be found in the OS filesystem.
+// Using the option again adds paths, but it replaces paths configured with WithFeaturesFS.
+// A feature matching more than one pattern runs once
+func WithFeaturesPath(paths ...string) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		features, warnings := []string{}, []string{}
+		if options.featuresFS == nil {
+			features = append(features, options.features...)
+			warnings = append(warnings, options.featureWarnings...)
+		}
+
+		for _, path := range paths {
+			matches, err := filepath.Glob(path)
+			if warning := featuresPatternWarning(path, matches, err); warning != "" {
+				warnings = append(warnings, warning)
+			}
+
+			features = appendUnique(features, matches...)
+		}
+
+		options.features = features
+		options.featureWarnings = warnings
+		options.featuresFS = nil
+	}
+}
+
+// featuresPatternWarning describes the problem of a pattern of features which is malformed or doesn't match any file.
+// It returns an empty string when the pattern matches features
+func featuresPatternWarning(pattern string, matches []string, err error) string {
+	if err != nil {
+		return fmt.Sprintf("gobdd: the features pattern %s is malformed: %s", pattern, err)
+	}
+
+	if len(matches) == 0 {
+		return fmt.Sprintf("gobdd: the features pattern %s doesn't match any file", pattern)
+	}
+
+	return ""
+}
+
+// appendUnique appends paths to features skipping the ones which are already there
+func appendUnique(features []string, paths ...string) []string {
+	for _, path := range paths {
+		found := false
+		for _, feature := range features {
+			if feature == path {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			features = append(features, path)
+		}
+	}
+
+	return features
+}
+
+// WithFeaturesRecursive configures the root directory in the OS filesystem which is searched recursively
+// for features. Only files which names match the pattern (e.g. *.feature) are executed.
+func WithFeaturesRecursive(root, pattern string) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.features = append([]string{}, findFeatures(root, pattern)...)
+		options.featuresFS = nil
+	}
+}
+
+// findFeatures walks the directory tree of the root and returns all files which names match the pattern
+func findFeatures(root, pattern string) []string {
+	var features []string
+
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if matched, _ := filepath.Match(pattern, d.Name()); matched && !d.IsDir() {
+			features = append(features, path)
+		}
+
+		return nil
+	})
+
+	return features
+}
+
+// WithExcludePaths excludes features which paths match any of the patterns (glob patterns) from the run
+func WithExcludePaths(patterns ...string) func(*SuiteOptions) {
 	return func(options *SuiteOptions) {
-		options.features = path
+		options.excludePaths = append(options.excludePaths, patterns...)
 	}
 }
 
@@ -79,421 +550,2010 @@ func WithTags(tags ...string) func(*SuiteOptions) {
 	}
 }
 
+// WithTagExpression configures a tag expression which scenarios have to match to be executed.
+// The expression supports `and`, `or`, `not` operators and parentheses, for example:
+//
+//	WithTagExpression("@smoke and not (@slow or @wip)")
+//
+// The expression should be valid, otherwise will produce an error and stop executing.
+func WithTagExpression(expr string) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		e, err := parseTagExpression(expr)
+		if err != nil {
+			panic(err.Error())
+		}
+
+		options.tagExpression = e
+	}
+}
+
+// WithBeforeFeature configures functions that should be executed before every feature
+func WithBeforeFeature(f func(ctx context.Context)) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.beforeFeature = append(options.beforeFeature, f)
+	}
+}
+
+// WithAfterFeature configures functions that should be executed after every feature,
+// even if any of its scenarios failed
+func WithAfterFeature(f func(ctx context.Context)) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.afterFeature = append(options.afterFeature, f)
+	}
+}
+
 // WithBeforeScenario configures functions that should be executed before every scenario
 func WithBeforeScenario(f func(ctx context.Context)) func(*SuiteOptions) {
 	return func(options *SuiteOptions) {
-		options.beforeScenario = append(options.beforeScenario, f)
+		options.beforeScenario = append(options.beforeScenario, scenarioHook{f: keepContext(f)})
+	}
+}
+
+// WithBeforeScenarioCtx configures functions that should be executed before every scenario.
+// The context returned by the function is used by the rest of the scenario
+func WithBeforeScenarioCtx(f func(ctx context.Context) context.Context) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.beforeScenario = append(options.beforeScenario, scenarioHook{f: f})
 	}
 }
 
-// WithAfterScenario configures functions that should be executed after every scenario
+// WithAfterScenario configures functions that should be executed after every scenario.
+// After scenario hooks are executed in reverse order of registration, the last registered first
 func WithAfterScenario(f func(ctx context.Context)) func(*SuiteOptions) {
 	return func(options *SuiteOptions) {
-		options.afterScenario = append(options.afterScenario, f)
+		options.afterScenario = append(options.afterScenario, scenarioHook{f: keepContext(f)})
+	}
+}
+
+// WithAfterScenarioCtx configures functions that should be executed after every scenario.
+// The context returned by the function is passed to the following after scenario functions
+func WithAfterScenarioCtx(f func(ctx context.Context) context.Context) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.afterScenario = append(options.afterScenario, scenarioHook{f: f})
+	}
+}
+
+// WithBeforeScenarioTagged configures functions that should be executed before every scenario with the given tag
+func WithBeforeScenarioTagged(tag string, f func(ctx context.Context)) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.beforeScenario = append(options.beforeScenario, scenarioHook{tag: tag, f: keepContext(f)})
+	}
+}
+
+// WithAfterScenarioTagged configures functions that should be executed after every scenario with the given tag
+func WithAfterScenarioTagged(tag string, f func(ctx context.Context)) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.afterScenario = append(options.afterScenario, scenarioHook{tag: tag, f: keepContext(f)})
+	}
+}
+
+// WithBeforeStep configures functions that should be executed before every step
+func WithBeforeStep(f func(ctx context.Context)) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.beforeStep = append(options.beforeStep, keepContext(f))
+	}
+}
+
+// WithBeforeStepCtx configures functions that should be executed before every step.
+// The context returned by the function is used by the step and the rest of the scenario
+func WithBeforeStepCtx(f func(ctx context.Context) context.Context) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.beforeStep = append(options.beforeStep, f)
+	}
+}
+
+// WithBeforeStepMatching configures functions that should be executed before every step
+// which text, without the keyword, matches the regular expression
+func WithBeforeStepMatching(pattern string, f func(ctx context.Context)) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.beforeStep = append(options.beforeStep, stepMatching(pattern, f))
+	}
+}
+
+// WithAfterStep configures functions that should be executed after every step.
+// After step hooks are executed in reverse order of registration, the last registered first
+func WithAfterStep(f func(ctx context.Context)) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.afterStep = append(options.afterStep, keepContext(f))
 	}
 }
 
-// WithBeforeStep configures functions that should be executed before every step
-func WithBeforeStep(f func(ctx context.Context)) func(*SuiteOptions) {
-	return func(options *SuiteOptions) {
-		options.beforeStep = append(options.beforeStep, f)
+// WithAfterStepMatching configures functions that should be executed after every step
+// which text, without the keyword, matches the regular expression
+func WithAfterStepMatching(pattern string, f func(ctx context.Context)) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.afterStep = append(options.afterStep, stepMatching(pattern, f))
+	}
+}
+
+// WithAfterStepCtx configures functions that should be executed after every step.
+// The context returned by the function is used by the rest of the scenario
+func WithAfterStepCtx(f func(ctx context.Context) context.Context) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.afterStep = append(options.afterStep, f)
+	}
+}
+
+// keepContext adapts a hook which doesn't return a context to the one returning the context it was called with
+func keepContext(f func(ctx context.Context)) func(ctx context.Context) context.Context {
+	return func(ctx context.Context) context.Context {
+		f(ctx)
+
+		return ctx
+	}
+}
+
+// WithIgnoredTags configures which tags should be skipped while executing a suite
+// Every tag has to start with @ otherwise will be ignored.
+// Ignored tags always take precedence: a scenario with an ignored tag is skipped
+// even if it matches WithTags or WithTagExpression
+func WithIgnoredTags(tags ...string) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.ignoreTags = tags
+	}
+}
+
+// scenarioHook is a function executed before or after scenarios.
+// When the tag is set, the hook is executed only for scenarios with the tag
+type scenarioHook struct {
+	tag string
+	f   func(ctx context.Context) context.Context
+}
+
+func (h scenarioHook) matches(tags []*msgs.Tag) bool {
+	if h.tag == "" {
+		return true
+	}
+
+	for _, tag := range tags {
+		if tag.Name == h.tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+type stepDef struct {
+	expr       *regexp.Regexp
+	f          interface{}
+	transforms map[string]transform
+	usage      *stepUsage
+	// keyword limits the step to steps of the keyword type. The step matches steps of any type when it's empty
+	keyword msgs.StepKeywordType
+	// listSeparator splits captured values passed to slice arguments
+	listSeparator string
+	// skipValidation passes values of parameter types with validations to the step function without checking them
+	skipValidation bool
+}
+
+// newStepDef creates a step definition using transforms and options of the suite.
+// All step definitions should be created with it, so they follow the configuration of the suite
+func (s *Suite) newStepDef(expr *regexp.Regexp, f interface{}, usage *stepUsage, keyword msgs.StepKeywordType) stepDef {
+	return stepDef{
+		expr:           expr,
+		f:              f,
+		transforms:     s.transforms,
+		usage:          usage,
+		keyword:        keyword,
+		listSeparator:  s.options.listSeparator,
+		skipValidation: s.options.skipValidation,
+	}
+}
+
+// takesPrecedence tells whether the step definition should be chosen over the other one when both match a step.
+// Definitions added for a keyword take precedence over definitions matching any keyword.
+// Otherwise the definition with the longer expression wins, and the one added first when they're equally long
+func (d stepDef) takesPrecedence(other stepDef) bool {
+	if (d.keyword != "") != (other.keyword != "") {
+		return d.keyword != ""
+	}
+
+	return len(d.usage.expr) > len(other.usage.expr)
+}
+
+// stepUsage counts how many times an added step matched steps of features
+type stepUsage struct {
+	expr    string
+	matches int64
+}
+
+// transform converts a value captured by a parameter type to the argument of a step function
+type transform struct {
+	convert func(string) (interface{}, error)
+	// validation tells that the transform only checks the value, so it's skipped with WithoutParameterValidation
+	validation bool
+}
+
+// Creates a new suites with given configuration and empty steps defined
+func NewSuite(optionClosures ...func(*SuiteOptions)) *Suite {
+	options := NewSuiteOptions()
+
+	for i := 0; i < len(optionClosures); i++ {
+		optionClosures[i](&options)
+	}
+
+	options.applyOverrides()
+
+	s := &Suite{
+		steps:          []stepDef{},
+		options:        options,
+		parameterTypes: map[string][]string{},
+		transforms:     map[string]transform{},
+	}
+
+	s.AddParameterTypes(`{int}`, []string{`(-?\d+)`})
+	s.AddParameterTypes(`{float}`, []string{`([-+]?\d*\.?\d*)`})
+	s.AddParameterTypes(`{word}`, []string{`([\d\w]+)`})
+	s.AddParameterTypes(`{string}`, []string{`"([^"]*)"`})
+	s.AddParameterTypeTransform(`{text}`, `"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'`, unquoteText)
+	s.AddParameterTypes(`{anything}`, []string{`(.+)`})
+	s.AddParameterTypes(`{duration}`, []string{`((?:\d+(?:\.\d+)?(?:ns|us|µs|ms|s|m|h))+)`})
+	s.addParameterTypeValidation(`{email}`, emailExpr, validateEmail)
+	s.addParameterTypeValidation(`{url}`, urlExpr, validateURL)
+
+	return s
+}
+
+// AddParameterTypes adds a list of parameter types that will be used to simplify step definitions.
+//
+// The first argument is the parameter type and the second parameter is a list of regular expressions
+// that should replace the parameter type.
+//
+//	s.AddParameterTypes(`{int}`, []string{`(\d)`})
+//
+// The regular expression should compile, otherwise will produce an error and stop executing.
+func (s *Suite) AddParameterTypes(from string, to []string) {
+	for _, to := range to {
+		_, err := regexp.Compile(to)
+		if err != nil {
+			panic(fmt.Sprintf(`the regular expresion for key %s doesn't compile: %s`, from, to))
+		}
+
+		s.parameterTypes[from] = append(s.parameterTypes[from], to)
+	}
+}
+
+// AddParameterTypeTransform adds a parameter type which value is converted by the transform function
+// before it's passed to the step function.
+//
+//	s.AddParameterTypeTransform(`{date}`, `\d{4}-\d{2}-\d{2}`, func(value string) (interface{}, error) {
+//		return time.Parse("2006-01-02", value)
+//	})
+//
+// The whole value matched by the regular expression is passed to the transform,
+// so the expression shouldn't contain capturing groups.
+// The type returned by the transform has to be assignable to the argument of the step function.
+// When the transform returns an error, the step fails.
+func (s *Suite) AddParameterTypeTransform(name, expr string, f func(string) (interface{}, error)) {
+	group := transformGroupName(name)
+	s.AddParameterTypes(name, []string{fmt.Sprintf(`(?P<%s>%s)`, group, expr)})
+	s.transforms[group] = transform{convert: f}
+}
+
+// addParameterTypeValidation adds a parameter type which value is passed to the step function as a string
+// after it's checked by the validation, unless the suite is configured with WithoutParameterValidation
+func (s *Suite) addParameterTypeValidation(name, expr string, validate func(string) error) {
+	group := transformGroupName(name)
+	s.AddParameterTypes(name, []string{fmt.Sprintf(`(?P<%s>%s)`, group, expr)})
+	s.transforms[group] = transform{
+		convert: func(value string) (interface{}, error) {
+			if err := validate(value); err != nil {
+				return nil, err
+			}
+
+			return value, nil
+		},
+		validation: true,
+	}
+}
+
+// unquoteText removes quotes around the text captured by {text} and unescapes characters preceded by a backslash
+func unquoteText(value string) (interface{}, error) {
+	value = value[1 : len(value)-1]
+
+	var b strings.Builder
+	escaped := false
+	for _, r := range value {
+		if r == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+
+		escaped = false
+		b.WriteRune(r)
+	}
+
+	return b.String(), nil
+}
+
+// emailExpr and urlExpr are loose on purpose, so malformed values are reported by the validation
+// instead of leaving steps undefined
+const (
+	emailExpr = `[^\s@]+@[^\s@]+`
+	urlExpr   = `[a-zA-Z][a-zA-Z0-9+.-]*://\S*`
+)
+
+// validateEmail checks that the value captured by {email} is a single email address without a display name
+func validateEmail(value string) error {
+	address, err := mail.ParseAddress(value)
+	if err != nil {
+		return fmt.Errorf("%s isn't a valid email: %s", value, err)
+	}
+
+	if address.Address != value {
+		return fmt.Errorf("%s isn't a valid email", value)
+	}
+
+	return nil
+}
+
+// validateURL checks that the value captured by {url} is an absolute URL with a host
+func validateURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("%s isn't a valid URL: %s", value, err)
+	}
+
+	if u.Host == "" {
+		return fmt.Errorf("%s isn't a valid URL: the host is missing", value)
+	}
+
+	return nil
+}
+
+// transformGroupName returns the name of the capturing group of the parameter type with a transform
+func transformGroupName(name string) string {
+	return "gobdd_" + regexp.MustCompile(`\W`).ReplaceAllString(name, "")
+}
+
+// AddStep registers a step in the suite.
+//
+// The second parameter is the step function that gets executed
+// when a step definition matches the provided regular expression.
+//
+// A step function can have any number of parameters.
+// It can accept a context.Context as the first parameter, preceded by gobdd.StepTest which is used to report failures:
+//
+//	func myStepFunction(t gobdd.StepTest, ctx context.Context, first int, second int) {
+//	}
+//
+// Steps which don't need the context can omit it:
+//
+//	func myStepFunction(first int, second int) {
+//	}
+//
+// Capturing groups of the expression have to match arguments of the step function, otherwise it panics.
+func (s *Suite) AddStep(expr string, step interface{}) {
+	if err := s.addStep(expr, step, &stepUsage{expr: expr}, ""); err != nil {
+		panic(fmt.Sprintf("the step function for step `%s` is incorrect: %s", expr, err))
+	}
+}
+
+// AddStepForKeyword adds a step which matches only steps with the keyword: Given, When or Then.
+// Steps following And or But have the type of the preceding step.
+// When both a step for the keyword and a step added with AddStep match, the step for the keyword is used.
+// It panics when the keyword isn't supported, otherwise it works the same way as AddStep
+func (s *Suite) AddStepForKeyword(keyword, expr string, step interface{}) {
+	keywordType, ok := keywordTypes[strings.ToLower(strings.TrimSpace(keyword))]
+	if !ok {
+		panic(fmt.Sprintf("the keyword %s isn't supported, use Given, When or Then", keyword))
+	}
+
+	if err := s.addStep(expr, step, &stepUsage{expr: expr}, keywordType); err != nil {
+		panic(fmt.Sprintf("the step function for step `%s` is incorrect: %s", expr, err))
+	}
+}
+
+// keywordTypes maps keywords accepted by AddStepForKeyword to types of steps
+var keywordTypes = map[string]msgs.StepKeywordType{
+	"given": msgs.StepKeywordType_CONTEXT,
+	"when":  msgs.StepKeywordType_ACTION,
+	"then":  msgs.StepKeywordType_OUTCOME,
+}
+
+// AddStepf adds a step with the expression formatted with fmt.Sprintf, e.g. when steps are generated in a loop.
+// It works the same way as AddStep
+func (s *Suite) AddStepf(format string, step interface{}, args ...interface{}) {
+	s.AddStep(fmt.Sprintf(format, args...), step)
+}
+
+// addStep adds a step definition for every variant of the expression.
+// All the variants share the usage, so the step is used when any of them matches
+func (s *Suite) addStep(expr string, step interface{}, usage *stepUsage, keyword msgs.StepKeywordType) error {
+	if err := validateStepFunc(step); err != nil {
+		return err
+	}
+
+	defs := []stepDef{}
+
+	for _, expr := range s.applyParameterTypes(expr) {
+		if s.options.ignoreCase {
+			expr = "(?i)" + expr
+		}
+
+		compiled, err := regexp.Compile(expr)
+		if err != nil {
+			return err
+		}
+
+		if err := validateStepArgs(compiled, step, s.transforms); err != nil {
+			return err
+		}
+
+		defs = append(defs, s.newStepDef(compiled, step, usage, keyword))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.steps = append(s.steps, defs...)
+
+	return nil
+}
+
+// applyParameterTypes replaces parameter types in the expression with their regular expressions.
+// It returns an expression for every combination of regular expressions of the parameter types
+func (s *Suite) applyParameterTypes(expr string) []string {
+	exprs := []string{expr}
+
+	for from, to := range s.parameterTypes {
+		if !strings.Contains(expr, from) {
+			continue
+		}
+
+		replaced := make([]string, 0, len(exprs)*len(to))
+		for _, expr := range exprs {
+			for _, t := range to {
+				replaced = append(replaced, strings.ReplaceAll(expr, from, t))
+			}
+		}
+
+		exprs = replaced
+	}
+
+	return exprs
+}
+
+// AddRegexStep registers a step in the suite.
+//
+// The second parameter is the step function that gets executed
+// when a step definition matches the provided regular expression.
+//
+// The step function has the same form as the one passed to AddStep:
+//
+//	func myStepFunction(t gobdd.StepTest, ctx context.Context, first int, second int) {
+//	}
+func (s *Suite) AddRegexStep(expr *regexp.Regexp, step interface{}) {
+	err := validateStepFunc(step)
+	if err == nil {
+		err = validateStepArgs(expr, step, s.transforms)
+	}
+
+	if err != nil {
+		panic(fmt.Sprintf("the step function is incorrect: %s", err))
+	}
+
+	usage := &stepUsage{expr: expr.String()}
+	if s.options.ignoreCase {
+		expr = regexp.MustCompile("(?i)" + expr.String())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.steps = append(s.steps, s.newStepDef(expr, step, usage, ""))
+}
+
+// StepInfo describes a step added to the suite
+type StepInfo struct {
+	// Expression is the expression of the step as it was added, before parameter types are replaced
+	Expression string
+	// ParameterTypes lists parameter types used in the expression in the order of their appearance
+	ParameterTypes []string
+	// Arguments is the number of arguments of the step function, not counting StepTest and the context
+	Arguments int
+	// Signature is the type of the step function
+	Signature string
+}
+
+// Steps returns information about all steps added to the suite in the order they were added
+func (s *Suite) Steps() []StepInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	steps := []StepInfo{}
+	seen := map[*stepUsage]bool{}
+
+	for _, def := range s.steps {
+		if seen[def.usage] {
+			continue
+		}
+		seen[def.usage] = true
+
+		f := reflect.TypeOf(def.f)
+
+		steps = append(steps, StepInfo{
+			Expression:     def.usage.expr,
+			ParameterTypes: s.parameterTypesOf(def.usage.expr),
+			Arguments:      f.NumIn() - argsOffset(f),
+			Signature:      f.String(),
+		})
+	}
+
+	return steps
+}
+
+// parameterTypesOf returns parameter types used in the expression in the order of their appearance
+func (s *Suite) parameterTypesOf(expr string) []string {
+	positions := map[int]string{}
+
+	for name := range s.parameterTypes {
+		for offset := 0; ; {
+			i := strings.Index(expr[offset:], name)
+			if i < 0 {
+				break
+			}
+
+			positions[offset+i] = name
+			offset += i + len(name)
+		}
+	}
+
+	indexes := make([]int, 0, len(positions))
+	for i := range positions {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	types := make([]string, 0, len(indexes))
+	for _, i := range indexes {
+		types = append(types, positions[i])
+	}
+
+	return types
+}
+
+// UnusedSteps returns expressions of added steps which didn't match any step of executed features
+func (s *Suite) UnusedSteps() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	unused := []string{}
+	seen := map[*stepUsage]bool{}
+
+	for _, def := range s.steps {
+		if seen[def.usage] {
+			continue
+		}
+		seen[def.usage] = true
+
+		if atomic.LoadInt64(&def.usage.matches) == 0 {
+			unused = append(unused, def.usage.expr)
+		}
+	}
+
+	return unused
+}
+
+// Executes the suite with given options and defined steps.
+//
+// When the suite is configured with WithDryRun, the returned error lists all undefined or invalid steps
+func (s *Suite) Run() error {
+	return s.run(nil)
+}
+
+// RunWithResult executes the suite like Run and returns the summary of results of the run
+func (s *Suite) RunWithResult() (Summary, error) {
+	start := time.Now()
+	err := s.run(nil)
+
+	summary := newSummary(s.results, s.options.strict)
+	summary.Duration = time.Since(start)
+
+	return summary, err
+}
+
+// RunCollect executes the suite like Run and returns results of all executed scenarios in the order they were executed.
+// It's meant for tests of suites which assert results of particular scenarios
+func (s *Suite) RunCollect() ([]CollectedScenario, error) {
+	err := s.run(nil)
+
+	return collectScenarios(s.results), err
+}
+
+// RunModels executes features of the suite with step definitions registered in the scheme
+// instead of steps added to the suite and returns their results.
+//
+// Step functions of the scheme return an error which fails the step, or models.ErrPending
+// which marks it as pending. Only paths of features and the context of the suite are used:
+// hooks, filters, formatters and reports are configured for Run and RunWithT.
+// An error is returned when a feature cannot be read or any of its steps has no matching step definition
+func (s *Suite) RunModels(scheme *models.Scheme) ([]*models.Feature, error) {
+	features := []*models.Feature{}
+
+	for _, featurePath := range s.featuresToRun() {
+		file, err := s.openFeature(featurePath)
+		if err != nil {
+			return features, fmt.Errorf("cannot open the feature file %s: %w", featurePath, err)
+		}
+
+		doc, err := gherkin.ParseGherkinDocument(bufio.NewReader(file), (&msgs.Incrementing{}).NewId)
+		file.Close()
+		if err != nil {
+			return features, fmt.Errorf("cannot parse the feature file %s: %w", featurePath, err)
+		}
+
+		if doc.Feature == nil {
+			continue
+		}
+
+		feature, err := models.NewFeature(doc.Feature, scheme)
+		if err != nil {
+			return features, fmt.Errorf("cannot load the feature file %s: %w", featurePath, err)
+		}
+
+		feature.Uri = featurePath
+		feature.Run(s.options.ctx)
+		features = append(features, feature)
+	}
+
+	return features, nil
+}
+
+// RunWithT executes the suite with given options and defined steps.
+//
+// Every feature and every scenario is run as a subtest of t.
+// Failed steps are reported together with their location in the feature file.
+func (s *Suite) RunWithT(t *testing.T) {
+	if err := s.run(t); err != nil {
+		t.Error(err)
+	}
+}
+
+func (s *Suite) run(t *testing.T) error {
+	s.mu.Lock()
+	s.undefinedSteps = nil
+	s.invalidSteps = nil
+	s.results = nil
+	for _, def := range s.steps {
+		atomic.StoreInt64(&def.usage.matches, 0)
+	}
+	s.mu.Unlock()
+
+	atomic.StoreInt32(&s.stopped, 0)
+
+	s.deadline = context.Background()
+	if s.options.suiteTimeout > 0 {
+		var cancel context.CancelFunc
+		s.deadline, cancel = context.WithTimeout(s.deadline, s.options.suiteTimeout)
+		defer cancel()
+	}
+
+	s.messages = newMessagesEmitter(s.options.messagesOutput)
+	s.messages.runStarted()
+	s.teamcity = newTeamCityOutput(s.options.teamcityOutput)
+
+	for _, warning := range s.options.featureWarnings {
+		s.warn(t, warning)
+	}
+
+	features := s.featuresToRun()
+
+	if len(features) == 0 {
+		if s.options.requireFeatures {
+			return errors.New("no feature files found, make sure the features path is correct")
+		}
+
+		s.warn(t, "gobdd: no feature files found, make sure the features path is correct")
+	}
+
+	for _, featurePath := range features {
+		if s.isStopped() {
+			break
+		}
+
+		feature, err := s.openFeature(featurePath)
+		if err != nil {
+			panic(fmt.Sprintf("cannot open the feature file %s: %s", featurePath, err))
+		}
+
+		doc, err := gherkin.ParseGherkinDocument(bufio.NewReader(feature), (&msgs.Incrementing{}).NewId)
+		if err != nil {
+			panic(fmt.Sprintf("error while loading document: %s\n", err))
+		}
+		defer feature.Close()
+
+		doc.Uri = featurePath
+		s.messages.gherkinDocument(doc)
+
+		if doc.Feature == nil {
+			continue
+		}
+
+		if t == nil {
+			s.runFeature(nil, doc)
+			continue
+		}
+
+		t.Run(doc.Feature.Name, func(t *testing.T) {
+			s.runFeature(t, doc)
+		})
+	}
+
+	if s.options.formatter != nil {
+		s.options.formatter.Summary(s.results)
+	} else if t == nil {
+		writeSummary(s.options.output, s.results)
+
+		if s.options.slowest > 0 {
+			writeSlowest(s.options.output, newSummary(s.results, s.options.strict).Slowest(s.options.slowest))
+		}
+	}
+
+	if err := s.messages.runFinished(newSummary(s.results, s.options.strict).Succeeded()); err != nil {
+		return fmt.Errorf("cannot write the messages output: %s", err)
+	}
+
+	if s.options.undefinedSnippets != nil {
+		writeSnippets(s.options.undefinedSnippets, s.undefinedSteps)
+	}
+
+	if s.options.jsonReport != nil {
+		if err := writeJSONReport(s.options.jsonReport, s.results); err != nil {
+			return fmt.Errorf("cannot write the JSON report: %s", err)
+		}
+	}
+
+	if s.options.junitReport != nil {
+		if err := writeJUnitReport(s.options.junitReport, s.results, s.options.strict); err != nil {
+			return fmt.Errorf("cannot write the JUnit report: %s", err)
+		}
+	}
+
+	if s.options.htmlReport != nil {
+		if err := writeHTMLReport(s.options.htmlReport, s.results); err != nil {
+			return fmt.Errorf("cannot write the HTML report: %s", err)
+		}
+	}
+
+	if s.options.rerunReport != "" {
+		if err := writeRerunReport(s.options.rerunReport, s.results, s.options.strict); err != nil {
+			return fmt.Errorf("cannot write the rerun report: %s", err)
+		}
+	}
+
+	if s.options.dryRun {
+		return s.dryRunError()
+	}
+
+	if s.timedOut() {
+		return fmt.Errorf("the suite timed out after %s", s.options.suiteTimeout)
+	}
+
+	return nil
+}
+
+// featuresToRun returns paths of features which aren't excluded
+func (s *Suite) featuresToRun() []string {
+	match := filepath.Match
+	if s.options.featuresFS != nil {
+		match = path.Match
+	}
+
+	features := []string{}
+	paths := append([]string{}, s.options.features...)
+	for _, feature := range s.options.inlineFeatures {
+		paths = appendUnique(paths, feature.name)
+	}
+
+	for _, feature := range paths {
+		excluded := false
+		for _, pattern := range s.options.excludePaths {
+			if matched, _ := match(pattern, feature); matched {
+				excluded = true
+				break
+			}
+		}
+
+		if !excluded && s.isChanged(feature) {
+			features = append(features, feature)
+		}
+	}
+
+	return features
+}
+
+// openFeature opens the inline feature with the path,
+// or the feature file from the configured filesystem or the OS filesystem
+func (s *Suite) openFeature(path string) (io.ReadCloser, error) {
+	for i := len(s.options.inlineFeatures) - 1; i >= 0; i-- {
+		if feature := s.options.inlineFeatures[i]; feature.name == path {
+			return io.NopCloser(strings.NewReader(feature.content)), nil
+		}
+	}
+
+	if s.options.featuresFS != nil {
+		return s.options.featuresFS.Open(path)
+	}
+
+	return os.Open(path)
+}
+
+// dryRunError aggregates all undefined and invalid steps found during a dry run
+func (s *Suite) dryRunError() error {
+	problems := []string{}
+	for _, step := range s.undefinedSteps {
+		problems = append(problems,
+			fmt.Sprintf("%s%s (line %d): undefined step", step.Keyword, step.Text, step.Location.Line))
+	}
+
+	problems = append(problems, s.invalidSteps...)
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("the dry run found %d invalid steps:\n%s", len(problems), strings.Join(problems, "\n"))
+}
+
+func (s *Suite) runFeature(t *testing.T, doc *msgs.GherkinDocument) {
+	feature := doc.Feature
+
+	for _, tag := range feature.Tags {
+		if contains(s.options.ignoreTags, tag.Name) {
+			return
+		}
+	}
+
+	result := &models.Feature{
+		Uri:         doc.Uri,
+		Location:    feature.Location,
+		Tags:        feature.Tags,
+		Language:    feature.Language,
+		Keyword:     feature.Keyword,
+		Name:        feature.Name,
+		Description: feature.Description,
+	}
+
+	s.mu.Lock()
+	s.results = append(s.results, result)
+	s.mu.Unlock()
+
+	if s.options.formatter != nil {
+		s.options.formatter.Feature(result)
+	}
+
+	s.teamcity.suiteStarted(result)
+	defer s.teamcity.suiteFinished(result)
+
+	ctx := s.options.ctx
+
+	// features starting after the suite timed out are reported with all scenarios skipped, without running hooks
+	if !s.timedOut() {
+		s.callBeforeFeatures(ctx)
+		defer s.callAfterFeatures(ctx)
+	}
+
+	var bkg *msgs.Background
+
+	wg := sync.WaitGroup{}
+	defer wg.Wait()
+
+	var sem chan struct{}
+	if s.options.maxParallel > 0 {
+		sem = make(chan struct{}, s.options.maxParallel)
+	}
+
+	for i, child := range feature.Children {
+		if s.isStopped() {
+			break
+		}
+
+		if child.Background != nil {
+			bkg = child.Background
+		}
+
+		if child.Scenario == nil {
+			continue
+		}
+
+		scenario := child.Scenario
+		if len(s.options.lineFilters) > 0 {
+			end := int64(-1)
+			if i+1 < len(feature.Children) {
+				end = childLine(feature.Children[i+1])
+			}
+
+			if scenario = s.filterByLines(doc.Uri, scenario, end); scenario == nil {
+				continue
+			}
+		}
+
+		if s.skipChild(feature.Tags, scenario) {
+			continue
+		}
+
+		// every row of examples of a scenario outline runs as a separate scenario
+		for _, scenario := range s.scenariosToRun(feature.Tags, scenario) {
+			if s.isStopped() {
+				break
+			}
+
+			if s.options.runInParallel {
+				wg.Add(1)
+
+				if sem != nil {
+					sem <- struct{}{}
+				}
+
+				go func(scenario *msgs.Scenario, bkg *msgs.Background) {
+					defer wg.Done()
+					if sem != nil {
+						defer func() { <-sem }()
+					}
+
+					s.runSubtest(t, result, scenario, bkg)
+				}(scenario, bkg)
+
+				continue
+			}
+
+			// NewScenario(ctx, featureChild)
+			s.runSubtest(t, result, scenario, bkg)
+		}
+	}
+}
+
+// filterByLines returns the scenario if it spans any of the lines filtered for the feature file.
+// The scenario spans lines from its keyword up to the line before end,
+// or up to the end of the file when end is negative.
+// When lines point to rows of examples, the returned copy of the scenario outline contains only these rows.
+// It returns nil when the scenario shouldn't be executed
+func (s *Suite) filterByLines(uri string, scenario *msgs.Scenario, end int64) *msgs.Scenario {
+	rows := map[*msgs.TableRow]bool{}
+
+	for _, line := range s.options.lineFilters[filepath.Clean(uri)] {
+		if line < scenario.Location.Line || (end >= 0 && line >= end) {
+			continue
+		}
+
+		row := exampleRow(scenario, line)
+		if row == nil {
+			return scenario
+		}
+
+		rows[row] = true
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+
+	filtered := *scenario
+	filtered.Examples = []*msgs.Examples{}
+
+	for _, example := range scenario.Examples {
+		body := []*msgs.TableRow{}
+		for _, row := range example.TableBody {
+			if rows[row] {
+				body = append(body, row)
+			}
+		}
+
+		if len(body) > 0 {
+			filteredExample := *example
+			filteredExample.TableBody = body
+			filtered.Examples = append(filtered.Examples, &filteredExample)
+		}
+	}
+
+	return &filtered
+}
+
+// exampleRow returns the row of examples of the scenario outline at the line
+func exampleRow(scenario *msgs.Scenario, line int64) *msgs.TableRow {
+	for _, example := range scenario.Examples {
+		for _, row := range example.TableBody {
+			if row.Location.Line == line {
+				return row
+			}
+		}
+	}
+
+	return nil
+}
+
+// childLine returns the line where the child of a feature starts
+func childLine(child *msgs.FeatureChild) int64 {
+	switch {
+	case child.Background != nil:
+		return child.Background.Location.Line
+	case child.Scenario != nil:
+		return child.Scenario.Location.Line
+	case child.Rule != nil:
+		return child.Rule.Location.Line
+	}
+
+	return -1
+}
+
+// runSubtest runs the scenario as a subtest of t when the suite is run with testing.T
+func (s *Suite) runSubtest(t *testing.T, feature *models.Feature, scenario *msgs.Scenario, bkg *msgs.Background) {
+	if t == nil {
+		s.runScenario(stdTest{w: s.options.output}, feature, scenario, bkg)
+		return
+	}
+
+	t.Run(scenario.Name, func(t *testing.T) {
+		s.runScenario(t, feature, scenario, bkg)
+	})
+}
+
+// scenariosToRun returns the scenario,
+// or a scenario for every row of examples of the scenario outline matching the tag filters.
+// Scenarios of rows are named after the outline with the number and values of the row,
+// and have tags of the outline combined with tags of their examples
+func (s *Suite) scenariosToRun(featureTags []*msgs.Tag, scenario *msgs.Scenario) []*msgs.Scenario {
+	if len(scenario.Examples) == 0 {
+		return []*msgs.Scenario{scenario}
+	}
+
+	scenarios := []*msgs.Scenario{}
+	toRun := s.examplesToRun(featureTags, scenario)
+	number := 0
+
+	for _, example := range scenario.Examples {
+		if example.TableHeader == nil {
+			continue
+		}
+
+		if !containsExamples(toRun, example) {
+			number += len(example.TableBody)
+			continue
+		}
+
+		rows := make([][]*msgs.Step, len(example.TableBody))
+		for _, outlineStep := range scenario.Steps {
+			for i, step := range s.stepsFromExamples(outlineStep, example) {
+				rows[i] = append(rows[i], step)
+			}
+		}
+
+		tags := combineTags(scenario.Tags, example.Tags)
+
+		for i, row := range example.TableBody {
+			number++
+
+			values := make([]string, 0, len(row.Cells))
+			for _, cell := range row.Cells {
+				values = append(values, cell.Value)
+			}
+
+			scenarios = append(scenarios, &msgs.Scenario{
+				Location:    row.Location,
+				Tags:        tags,
+				Keyword:     scenario.Keyword,
+				Name:        fmt.Sprintf("%s example #%d (%s)", scenario.Name, number, strings.Join(values, ", ")),
+				Description: scenario.Description,
+				Steps:       rows[i],
+				Id:          scenario.Id,
+			})
+		}
+	}
+
+	return scenarios
+}
+
+// containsExamples tells whether the examples are in the list
+func containsExamples(list []*msgs.Examples, examples *msgs.Examples) bool {
+	for _, e := range list {
+		if e == examples {
+			return true
+		}
+	}
+
+	return false
+}
+
+// generates steps
+func (s *Suite) stepsFromExamples(sourceStep *msgs.Step, example *msgs.Examples) []*msgs.Step {
+	steps := []*msgs.Step{}
+
+	if example.TableHeader == nil {
+		return steps
+	}
+
+	placeholders := example.TableHeader.Cells
+	placeholdersValues := []string{}
+
+	for _, placeholder := range placeholders {
+		ph := "<" + placeholder.Value + ">"
+		placeholdersValues = append(placeholdersValues, ph)
+	}
+
+	text := sourceStep.Text
+
+	for _, row := range example.TableBody {
+		// iterate over the cells and update the text.
+		// The step is matched with step definitions when it's executed, like any other step
+		stepText := s.stepFromExample(text, row, placeholdersValues)
+
+		// clone a step
+		step := &msgs.Step{
+			Id:          sourceStep.Id,
+			Location:    sourceStep.Location,
+			Keyword:     sourceStep.Keyword,
+			KeywordType: sourceStep.KeywordType,
+			Text:        stepText,
+			DocString:   docStringFromExample(sourceStep.DocString, row, placeholdersValues),
+			DataTable:   dataTableFromExample(sourceStep.DataTable, row, placeholdersValues),
+		}
+
+		steps = append(steps, step)
+	}
+
+	return steps
+}
+
+// replacePlaceholders replaces placeholders in the text with values of the examples row.
+// All placeholders are replaced at once, so values containing other placeholders are kept as they are
+func replacePlaceholders(text string, row *msgs.TableRow, placeholders []string) string {
+	values := make([]string, 0, len(placeholders)*2)
+	for i, ph := range placeholders {
+		values = append(values, ph, row.Cells[i].Value)
+	}
+
+	return strings.NewReplacer(values...).Replace(text)
+}
+
+// docStringFromExample returns a copy of the doc string with placeholders replaced with values of the examples row
+func docStringFromExample(docString *msgs.DocString, row *msgs.TableRow, placeholders []string) *msgs.DocString {
+	if docString == nil {
+		return nil
+	}
+
+	clone := *docString
+	clone.Content = replacePlaceholders(docString.Content, row, placeholders)
+
+	return &clone
+}
+
+// dataTableFromExample returns a copy of the data table
+// with placeholders in its cells replaced with values of the examples row
+func dataTableFromExample(dataTable *msgs.DataTable, row *msgs.TableRow, placeholders []string) *msgs.DataTable {
+	if dataTable == nil {
+		return nil
+	}
+
+	clone := &msgs.DataTable{Location: dataTable.Location, Rows: make([]*msgs.TableRow, 0, len(dataTable.Rows))}
+	for _, tableRow := range dataTable.Rows {
+		cells := make([]*msgs.TableCell, 0, len(tableRow.Cells))
+		for _, cell := range tableRow.Cells {
+			cells = append(cells, &msgs.TableCell{
+				Location: cell.Location,
+				Value:    replacePlaceholders(cell.Value, row, placeholders),
+			})
+		}
+
+		clone.Rows = append(clone.Rows, &msgs.TableRow{Id: tableRow.Id, Location: tableRow.Location, Cells: cells})
+	}
+
+	return clone
+}
+
+// stepFromExample returns the text of the step with placeholders replaced with values of the examples row
+func (s *Suite) stepFromExample(stepName string, row *msgs.TableRow, placeholders []string) string {
+	return replacePlaceholders(stepName, row, placeholders)
+}
+
+func (s *Suite) callBeforeFeatures(ctx context.Context) {
+	if s.options.dryRun {
+		return
+	}
+
+	for _, f := range s.options.beforeFeature {
+		f(ctx)
+	}
+}
+
+func (s *Suite) callAfterFeatures(ctx context.Context) {
+	if s.options.dryRun {
+		return
+	}
+
+	for _, f := range s.options.afterFeature {
+		f(ctx)
+	}
+}
+
+func (s *Suite) callBeforeScenarios(ctx context.Context, tags []*msgs.Tag) context.Context {
+	if s.options.dryRun {
+		return ctx
+	}
+
+	for _, h := range s.options.beforeScenario {
+		if h.matches(tags) {
+			ctx = h.f(ctx)
+		}
+	}
+
+	return ctx
+}
+
+// callAfterScenarios executes after scenario hooks in reverse order, so teardowns mirror setups
+func (s *Suite) callAfterScenarios(ctx context.Context, tags []*msgs.Tag) {
+	if s.options.dryRun {
+		return
+	}
+
+	for i := len(s.options.afterScenario) - 1; i >= 0; i-- {
+		if h := s.options.afterScenario[i]; h.matches(tags) {
+			ctx = h.f(ctx)
+		}
+	}
+}
+
+// stepMatching wraps the step hook so it's executed only for steps which text matches the pattern
+func stepMatching(pattern string, f func(ctx context.Context)) func(ctx context.Context) context.Context {
+	expr, err := regexp.Compile(pattern)
+	if err != nil {
+		panic(fmt.Sprintf("the step pattern %s doesn't compile: %s", pattern, err))
+	}
+
+	return func(ctx context.Context) context.Context {
+		if step := CurrentStep(ctx); step != nil && expr.MatchString(step.Text) {
+			f(ctx)
+		}
+
+		return ctx
+	}
+}
+
+func (s *Suite) callBeforeSteps(ctx context.Context) context.Context {
+	for _, f := range s.options.beforeStep {
+		ctx = f(ctx)
+	}
+
+	return ctx
+}
+
+// callAfterSteps executes after step hooks in reverse order, so teardowns mirror setups
+func (s *Suite) callAfterSteps(ctx context.Context) context.Context {
+	for i := len(s.options.afterStep) - 1; i >= 0; i-- {
+		ctx = s.options.afterStep[i](ctx)
+	}
+
+	return ctx
+}
+
+func (s *Suite) runScenario(t StepTest, feature *models.Feature, scenario *msgs.Scenario, bkg *msgs.Background) {
+
+	// TODO create kubernetes scenario
+	// kubernetes scenario should incorporate runScenario, run, runStep, findStepDef and paramType
+
+	result := &models.Scenario{
+		Location:    scenario.Location,
+		Tags:        scenario.Tags,
+		Keyword:     scenario.Keyword,
+		Name:        scenario.Name,
+		Description: scenario.Description,
+		Background:  bkg,
+	}
+
+	s.mu.Lock()
+	feature.Scenarios = append(feature.Scenarios, result)
+	s.mu.Unlock()
+
+	if s.options.formatter != nil {
+		s.options.formatter.Scenario(result)
+	}
+
+	s.scenarioStarted(result)
+	s.teamcity.testStarted(feature, result)
+
+	start := time.Now()
+	defer func() {
+		s.messages.scenarioFinished(feature, scenario, result, start, time.Now())
+		s.teamcity.testFinished(feature, result, time.Since(start))
+		s.scenarioFinished(result)
+	}()
+
+	ctx := withFeatureURI(withScenarioResult(withScenario(s.options.ctx, scenario), result), feature.Uri)
+	if s.options.stepOutput != nil {
+		ctx = models.WithRunContext(ctx, s.options.stepOutput)
+	}
+
+	// scenarios with the @skip tag, or starting after the suite timed out,
+	// are reported with all steps skipped, without running hooks
+	if s.skippedByTag(feature.Tags) || s.skippedByTag(scenario.Tags) || s.timedOut() {
+		_ = s.runStepsWithBackground(ctx, t, result, bkg, scenario.Steps, true)
+		return
+	}
+
+	var failed bool
+	for attempt := 0; ; attempt++ {
+		// failures of attempts which are retried are collected instead of failing the test
+		last := attempt >= s.options.scenarioRetries
+		attemptT := t
+		if !last {
+			attemptT = newStepTest(t)
+		}
+
+		if s.options.scenarioRetries > 0 {
+			result.Attempts = attempt + 1
+		}
+
+		result.Steps = nil
+		err := s.runScenarioAttempt(ctx, attemptT, result, scenario, bkg)
+
+		failed = err != nil && (err != errPendingStep || s.options.strict)
+		if !failed || last {
+			break
+		}
+
+		t.Logf("%s: attempt %d failed, retrying: %s", scenario.Name, attempt+1, err)
+	}
+
+	if failed {
+		s.scenarioFailed()
+	}
+}
+
+// runScenarioAttempt runs the scenario hooks, the background and the steps of the scenario once
+func (s *Suite) runScenarioAttempt(
+	ctx context.Context, t StepTest, result *models.Scenario, scenario *msgs.Scenario, bkg *msgs.Background,
+) error {
+	ctx = s.callBeforeScenarios(ctx, scenario.Tags)
+	defer s.callAfterScenarios(ctx, scenario.Tags)
+
+	if s.options.scenarioTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.options.scenarioTimeout)
+		defer cancel()
+	}
+
+	// the context is cancelled once the steps are finished, right after the failed step if any of them fails,
+	// so goroutines started by the steps can stop
+	stepsCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	return s.runStepsWithBackground(stepsCtx, t, result, bkg, scenario.Steps, false)
+}
+
+// runStepsWithBackground runs the background steps followed by the steps.
+// All of them are recorded as skipped when skip is true
+func (s *Suite) runStepsWithBackground(
+	ctx context.Context, t StepTest, result *models.Scenario, bkg *msgs.Background, steps []*msgs.Step, skip bool,
+) error {
+	var err error
+
+	if bkg != nil {
+		ctx, err = s.runSteps(ctx, t, result, bkg.Steps, skip)
+	}
+
+	if _, stepsErr := s.runSteps(ctx, t, result, steps, skip || err != nil); stepsErr != nil {
+		err = stepsErr
 	}
+
+	return err
 }
 
-// WithAfterStep configures functions that should be executed after every step
-func WithAfterStep(f func(ctx context.Context)) func(*SuiteOptions) {
-	return func(options *SuiteOptions) {
-		options.afterStep = append(options.afterStep, f)
+// scenarioFailed stops running further scenarios when the suite is configured to fail fast
+func (s *Suite) scenarioFailed() {
+	if s.options.failFast {
+		atomic.StoreInt32(&s.stopped, 1)
 	}
 }
 
-// WithIgnoredTags configures which tags should be skipped while executing a suite
-// Every tag has to start with @ otherwise will be ignored
-func WithIgnoredTags(tags ...string) func(*SuiteOptions) {
-	return func(options *SuiteOptions) {
-		options.ignoreTags = tags
-	}
+// timedOut tells whether the run took longer than the timeout of the suite
+func (s *Suite) timedOut() bool {
+	return s.deadline != nil && s.deadline.Err() != nil
 }
 
-type stepDef struct {
-	expr *regexp.Regexp
-	f    interface{}
+func (s *Suite) isStopped() bool {
+	return atomic.LoadInt32(&s.stopped) == 1
 }
 
-// Creates a new suites with given configuration and empty steps defined
-func NewSuite(optionClosures ...func(*SuiteOptions)) *Suite {
-	options := NewSuiteOptions()
+// runSteps executes steps one by one until any of them fails and records their results in the scenario.
+// Steps following the failed one, or all of them when skip is true, are recorded as skipped
+func (s *Suite) runSteps(
+	ctx context.Context, t StepTest, scenario *models.Scenario, steps []*msgs.Step, skip bool,
+) (context.Context, error) {
+	var err error
+	previous := msgs.StepKeywordType_UNKNOWN
+
+	for _, step := range steps {
+		result := &models.Step{
+			Id:          step.Id,
+			Location:    step.Location,
+			Keyword:     step.Keyword,
+			KeywordType: models.ResolveKeywordType(step.KeywordType, previous),
+			Text:        step.Text,
+			DocString:   step.DocString,
+			DataTable:   step.DataTable,
+		}
+		scenario.Steps = append(scenario.Steps, result)
+		previous = result.KeywordType
+
+		if skip || err != nil {
+			result.Execution.Result = models.Skipped
+		} else {
+			result.Execution.StartTime = time.Now()
+			ctx, err = s.runStep(ctx, t, step, result)
+			result.Execution.EndTime = time.Now()
+
+			if err != nil {
+				result.Execution.Result = models.Failed
+				result.Execution.Err = err
+			}
 
-	for i := 0; i < len(optionClosures); i++ {
-		optionClosures[i](&options)
+			var panicErr *panicError
+			if errors.As(err, &panicErr) {
+				result.Execution.Stack = panicErr.stack
+			}
+
+			switch err {
+			case errUndefinedStep:
+				result.Execution.Result = models.Undefined
+			case errPendingStep:
+				result.Execution.Result = models.Pending
+			}
+		}
+
+		if s.options.formatter != nil {
+			s.options.formatter.Step(result, result.Execution.Result, result.Execution.Duration())
+		}
+
+		s.stepFinished(result)
 	}
 
-	s := &Suite{
-		steps:          []stepDef{},
-		options:        options,
-		parameterTypes: map[string][]string{},
+	return ctx, err
+}
+
+// errUndefinedStep is returned when there's no step definition matching the step
+var errUndefinedStep = errors.New("undefined step")
+
+// errPendingStep is returned when the step function called Pending
+var errPendingStep = errors.New("pending step")
+
+// runStep executes the step and returns the context which should be passed to the next step
+func (s *Suite) runStep(
+	ctx context.Context, t StepTest, step *msgs.Step, result *models.Step,
+) (context.Context, error) {
+	text := step.Text
+	if s.options.normalizeSpace {
+		text = normalizeWhitespace(text)
 	}
 
-	s.AddParameterTypes(`{int}`, []string{`(\d)`})
-	s.AddParameterTypes(`{float}`, []string{`([-+]?\d*\.?\d*)`})
-	s.AddParameterTypes(`{word}`, []string{`([\d\w]+)`})
-	s.AddParameterTypes(`{text}`, []string{`"([\d\w\-\s]+)"`, `'([\d\w\-\s]+)'`})
+	def, err := s.resolveStep(text, result.KeywordType)
 
-	return s
-}
+	var invalidErr *invalidStepError
+	if errors.As(err, &invalidErr) {
+		if s.options.dryRun {
+			s.mu.Lock()
+			s.invalidSteps = append(s.invalidSteps,
+				fmt.Sprintf("%s%s (line %d): %s", step.Keyword, step.Text, step.Location.Line, err))
+			s.mu.Unlock()
 
-// AddParameterTypes adds a list of parameter types that will be used to simplify step definitions.
-//
-// The first argument is the parameter type and the second parameter is a list of regular expressions
-// that should replace the parameter type.
-//
-//	s.AddParameterTypes(`{int}`, []string{`(\d)`})
-//
-// The regular expression should compile, otherwise will produce an error and stop executing.
-func (s *Suite) AddParameterTypes(from string, to []string) {
-	for _, to := range to {
-		_, err := regexp.Compile(to)
-		if err != nil {
-			panic(fmt.Sprintf(`the regular expresion for key %s doesn't compile: %s`, from, to))
+			return ctx, nil
 		}
 
-		s.parameterTypes[from] = append(s.parameterTypes[from], to)
+		t.Errorf("%s%s (%s): %s", step.Keyword, step.Text, stepLocation(ctx, step), err)
+
+		return ctx, err
 	}
-}
 
-// AddStep registers a step in the suite.
-//
-// The second parameter is the step function that gets executed
-// when a step definition matches the provided regular expression.
-//
-// A step function can have any number of parameters (even zero),
-// but it MUST accept a gobdd.StepTest and gobdd.Context as the first parameters (if there is any):
-//
-//	func myStepFunction(t gobdd.StepTest, ctx gobdd.Context, first int, second int) {
-//	}
-func (s *Suite) AddStep(expr string, step interface{}) {
-	err := validateStepFunc(step)
 	if err != nil {
-		panic(fmt.Sprintf("the step function for step `%s` is incorrect: %w", expr, err))
-	}
+		s.mu.Lock()
+		s.undefinedSteps = append(s.undefinedSteps, step)
+		s.mu.Unlock()
 
-	exprs := s.applyParameterTypes(expr)
+		if s.options.dryRun {
+			return ctx, nil
+		}
 
-	for _, expr := range exprs {
-		compiled := regexp.MustCompile(expr)
-		s.steps = append(s.steps, stepDef{
-			expr: compiled,
-			f:    step,
-		})
+		t.Errorf("%s%s (%s): undefined step", step.Keyword, step.Text, stepLocation(ctx, step))
+
+		return ctx, errUndefinedStep
 	}
-}
 
-func (s *Suite) applyParameterTypes(expr string) []string {
-	exprs := []string{expr}
+	params := def.expr.FindSubmatch([]byte(text))[1:]
 
-	for from, to := range s.parameterTypes {
-		for _, t := range to {
-			if strings.Contains(expr, from) {
-				exprs = append(exprs, strings.ReplaceAll(expr, from, t))
-			}
+	if s.options.dryRun {
+		if _, err := def.args(ctx, t, step, params); err != nil {
+			s.mu.Lock()
+			s.invalidSteps = append(s.invalidSteps,
+				fmt.Sprintf("%s%s (line %d): %s", step.Keyword, step.Text, step.Location.Line, err))
+			s.mu.Unlock()
 		}
+
+		return ctx, nil
 	}
 
-	return exprs
-}
+	if ctx.Err() != nil {
+		msg := s.scenarioDoneReason(ctx)
+		t.Errorf("%s%s (%s): %s", step.Keyword, step.Text, stepLocation(ctx, step), msg)
 
-// AddRegexStep registers a step in the suite.
-//
-// The second parameter is the step function that gets executed
-// when a step definition matches the provided regular expression.
-//
-// A step function can have any number of parameters (even zero),
-// but it MUST accept a gobdd.StepTest and gobdd.Context as the first parameters (if there is any):
-//
-//	func myStepFunction(t gobdd.StepTest, ctx gobdd.Context, first int, second int) {
-//	}
-func (s *Suite) AddRegexStep(expr *regexp.Regexp, step interface{}) {
-	err := validateStepFunc(step)
-	if err != nil {
-		panic(fmt.Sprintf("the step function is incorrect: %w", err))
+		return ctx, errors.New(msg)
 	}
 
-	s.steps = append(s.steps, stepDef{
-		expr: expr,
-		f:    step,
-	})
-}
+	ctx = s.callBeforeSteps(withStep(ctx, result))
 
-// Executes the suite with given options and defined steps
-func (s *Suite) Run() {
+	st := newStepTest(t)
+	newCtx := s.runStepDef(ctx, def, st, step, params)
 
-	for _, featurePath := range s.options.features {
-		feature, err := os.Open(featurePath)
+	for retry := 0; st.Failed() && retry < s.options.stepRetries && ctx.Err() == nil; retry++ {
+		time.Sleep(s.options.stepBackoff)
 
-		doc, err := gherkin.ParseGherkinDocument(bufio.NewReader(feature), (&msgs.Incrementing{}).NewId)
-		if err != nil {
-			panic(fmt.Sprintf("error while loading document: %s\n", err))
+		st = newStepTest(t)
+		newCtx = s.runStepDef(ctx, def, st, step, params)
+	}
+
+	// the result and the error are known to after step hooks
+	result.Args = st.Args()
+
+	var stepErr error
+	switch {
+	case st.Failed():
+		stepErr = errors.New(strings.Join(st.Errors(), "; "))
+		if stack := st.Stack(); stack != nil {
+			stepErr = &panicError{msg: stepErr.Error(), stack: stack}
 		}
-		defer feature.Close()
 
-		if doc.Feature == nil {
-			continue
+		result.Execution.Result = models.Failed
+		result.Execution.Err = stepErr
+	case st.Pending():
+		result.Execution.Result = models.Pending
+	}
+
+	ctx = s.callAfterSteps(withStep(newCtx, result))
+
+	if st.Pending() && !st.Failed() {
+		if s.options.strict {
+			t.Errorf("%s%s (%s): pending step", step.Keyword, step.Text, stepLocation(ctx, step))
+		} else {
+			t.Logf("%s%s (%s): pending step", step.Keyword, step.Text, stepLocation(ctx, step))
 		}
 
-		s.runFeature(doc.Feature)
+		return ctx, errPendingStep
+	}
+
+	if st.Failed() {
+		t.Errorf("%s%s (%s): %s", step.Keyword, step.Text, stepLocation(ctx, step), stepErr)
+
+		return ctx, stepErr
 	}
+
+	return ctx, nil
 }
 
-func (s *Suite) runFeature(feature *msgs.Feature) {
-	for _, tag := range feature.Tags {
-		if contains(s.options.ignoreTags, tag.Name) {
-			return
-		}
+// stepLocation returns the path of the feature file and the line of the step, e.g. features/login.feature:12,
+// so failures point at the step in the feature. Only the line is returned when the path isn't known
+func stepLocation(ctx context.Context, step *msgs.Step) string {
+	if uri := featureURIFromContext(ctx); uri != "" {
+		return fmt.Sprintf("%s:%d", uri, line(step.Location))
 	}
 
-	for _, child := range feature.Children {
-		if child.Scenario == nil {
-			continue
+	return fmt.Sprintf("line %d", line(step.Location))
+}
+
+// scenarioDoneReason describes why the context of the scenario is done. Only an expired scenario timeout
+// is reported as a timeout, while other cancellations, e.g. of the context passed with WithContext,
+// are reported as such
+func (s *Suite) scenarioDoneReason(ctx context.Context) string {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) && s.options.scenarioTimeout > 0 {
+		return fmt.Sprintf("the scenario timed out after %s", s.options.scenarioTimeout)
+	}
+
+	return fmt.Sprintf("the scenario was cancelled: %v", ctx.Err())
+}
+
+// runStepDef executes the step function within the step or the scenario timeout if there's any
+func (s *Suite) runStepDef(
+	ctx context.Context, def stepDef, st *stepTest, step *msgs.Step, params [][]byte,
+) context.Context {
+	if s.options.stepTimeout <= 0 && s.options.scenarioTimeout <= 0 {
+		return def.run(ctx, st, step, params)
+	}
+
+	stepCtx := ctx
+	if s.options.stepTimeout > 0 {
+		var cancel context.CancelFunc
+		stepCtx, cancel = context.WithTimeout(ctx, s.options.stepTimeout)
+		defer cancel()
+	}
+
+	done := make(chan context.Context, 1)
+	go func() {
+		done <- def.run(stepCtx, st, step, params)
+	}()
+
+	select {
+	case newCtx := <-done:
+		if newCtx == stepCtx {
+			return ctx
 		}
 
-		if s.skipScenario(child.Scenario.Tags) {
-			continue
+		return detachedContext{Context: newCtx, parent: ctx}
+	case <-stepCtx.Done():
+		if ctx.Err() != nil {
+			st.Errorf("%s", s.scenarioDoneReason(ctx))
+		} else {
+			st.Errorf("the step timed out after %s", s.options.stepTimeout)
 		}
 
-		// NewScenario(ctx, featureChild)
-		s.runScenario(child.Scenario, child.Background)
+		return ctx
 	}
 }
 
-func (s *Suite) getOutlineStep(steps []*msgs.Step, examples []*msgs.Examples) []*msgs.Step {
-	stepsList := make([][]*msgs.Step, len(steps))
+// run executes the step function. Failures are reported to t.
+// If the step function returns a context, it is returned so it can be passed to the next step
+func (def *stepDef) run(ctx context.Context, t StepTest, step *msgs.Step, params [][]byte) (newCtx context.Context) {
+	newCtx = ctx
+
+	defer func() {
+		r := recover()
+		if r == errPending {
+			if st, ok := t.(*stepTest); ok {
+				st.markPending()
+			}
+
+			return
+		}
 
-	for i, outlineStep := range steps {
-		for _, example := range examples {
-			stepsList[i] = append(stepsList[i], s.stepsFromExamples(outlineStep, example)...)
+		if r != nil && r != errFailNow {
+			t.Error(r)
+
+			if st, ok := t.(*stepTest); ok {
+				st.recordStack(debug.Stack())
+			}
 		}
-	}
+	}()
 
-	var newSteps []*msgs.Step
+	in, err := def.args(ctx, t, step, params)
+	if err != nil {
+		t.Error(err)
 
-	if len(stepsList) == 0 {
-		return newSteps
+		return newCtx
 	}
 
-	for ei := range examples {
-		for ci := range examples[ei].TableBody {
-			for si := range steps {
-				newSteps = append(newSteps, stepsList[si][ci])
-			}
+	if st, ok := t.(*stepTest); ok {
+		st.recordArgs(in[argsOffset(reflect.TypeOf(def.f)):])
+	}
+
+	for _, out := range reflect.ValueOf(def.f).Call(in) {
+		if returned, ok := out.Interface().(context.Context); ok && returned != nil {
+			newCtx = returned
 		}
 	}
 
-	return newSteps
+	return newCtx
 }
 
-// generates steps
-func (s *Suite) stepsFromExamples(sourceStep *msgs.Step, example *msgs.Examples) []*msgs.Step {
-	steps := []*msgs.Step{}
-
-	placeholders := example.TableHeader.Cells
-	placeholdersValues := []string{}
+// args builds arguments of the step function from the captured parameters
+func (def *stepDef) args(ctx context.Context, t StepTest, step *msgs.Step, params [][]byte) ([]reflect.Value, error) {
+	d := reflect.TypeOf(def.f)
 
-	for _, placeholder := range placeholders {
-		ph := "<" + placeholder.Value + ">"
-		placeholdersValues = append(placeholdersValues, ph)
+	in := []reflect.Value{}
+	if acceptsStepTest(d) {
+		in = append(in, reflect.ValueOf(t))
 	}
 
-	text := sourceStep.Text
+	if acceptsContext(d) {
+		in = append(in, reflect.ValueOf(ctx))
+	}
 
-	for _, row := range example.TableBody {
-		// iterate over the cells and update the text
-		stepText, expr := s.stepFromExample(text, row, placeholdersValues)
+	offset := len(in)
 
-		// find step definition for the new step
-		def, err := s.findStepDef(stepText)
+	if d.NumIn() == offset+1 {
+		arg, ok, err := def.namedArgument(d.In(offset), params)
 		if err != nil {
-			continue
+			return nil, fmt.Errorf("the argument %d of the step function %s cannot be filled: %s", offset, d, err)
 		}
 
-		// add the step to the list
-		s.AddStep(expr, def.f)
-
-		// clone a step
-		step := &msgs.Step{
-			Location: sourceStep.Location,
-			Keyword:  sourceStep.Keyword,
-			Text:     stepText,
-			// TODO clone DocString and DocTable
+		if ok {
+			return append(in, arg), nil
 		}
+	}
 
-		steps = append(steps, step)
+	groups := argumentGroups(def.expr, d)
+	expected := len(groups) + offset
+	arg, hasArg := stepArgument(d, step, expected)
+	if hasArg {
+		expected++
+	} else if d.NumIn() == expected+1 && d.In(expected).Kind() == reflect.String {
+		// a trailing string argument without a capturing group is accepted by AddStep only for doc strings
+		return nil, fmt.Errorf("the step function %s expects a doc string but the step has none", d)
 	}
 
-	return steps
-}
+	if expected != d.NumIn() {
+		return nil, fmt.Errorf("the step function %s accepts %d arguments but %d received", d, d.NumIn(), expected)
+	}
 
-func (s *Suite) stepFromExample(stepName string, row *msgs.TableRow, placeholders []string) (string, string) {
-	expr := stepName
+	for j, i := range groups {
+		inType := d.In(j + offset)
 
-	for i, ph := range placeholders {
-		t := getRegexpForVar(row.Cells[i].Value)
-		expr = strings.ReplaceAll(expr, ph, t)
-		stepName = strings.ReplaceAll(stepName, ph, row.Cells[i].Value)
-	}
+		paramType, err := def.paramType(i, params[i], inType)
+		if err != nil {
+			return nil, fmt.Errorf("the argument %d of the step function %s cannot be converted: %s", j+offset, d, err)
+		}
 
-	return stepName, expr
-}
+		if !paramType.IsValid() || !paramType.Type().AssignableTo(inType) {
+			return nil, fmt.Errorf("the argument %d of the step function %s has unsupported type %s", j+offset, d, inType)
+		}
 
-func (s *Suite) callBeforeScenarios(ctx context.Context) {
-	for _, f := range s.options.beforeScenario {
-		f(ctx)
+		in = append(in, paramType)
 	}
-}
 
-func (s *Suite) callAfterScenarios(ctx context.Context) {
-	for _, f := range s.options.afterScenario {
-		f(ctx)
+	if hasArg {
+		in = append(in, arg)
 	}
-}
 
-func (s *Suite) callBeforeSteps(ctx context.Context) {
-	for _, f := range s.options.beforeStep {
-		f(ctx)
-	}
+	return in, nil
 }
 
-func (s *Suite) callAfterSteps(ctx context.Context) {
-	for _, f := range s.options.afterStep {
-		f(ctx)
+// namedArgument builds a struct or a map[string]string from named capturing groups of the step expression.
+// Fields of the struct are matched by the gobdd tag or, when there's no tag, by their names ignoring case.
+// It returns false if the type isn't a struct or map[string]string or the expression has no named groups
+// other than groups of parameter types with transforms
+func (def *stepDef) namedArgument(inType reflect.Type, params [][]byte) (reflect.Value, bool, error) {
+	names := def.expr.SubexpNames()[1:]
+	if !hasNamedGroups(def.expr, def.transforms) {
+		return reflect.Value{}, false, nil
 	}
-}
 
-func (s *Suite) runScenario(scenario *msgs.Scenario, bkg *msgs.Background) {
+	switch {
+	case isStringMap(inType):
+		m := reflect.MakeMapWithSize(inType, len(names))
+		for i, name := range names {
+			if name != "" && i < len(params) {
+				key := reflect.ValueOf(name).Convert(inType.Key())
+				m.SetMapIndex(key, reflect.ValueOf(string(params[i])).Convert(inType.Elem()))
+			}
+		}
 
-	// TODO create kubernetes scenario
-	// kubernetes scenario should incorporate runScenario, run, runStep, findStepDef and paramType
+		return m, true, nil
+	case inType.Kind() == reflect.Struct:
+		v := reflect.New(inType).Elem()
+		for i, name := range names {
+			field, ok := namedField(inType, name)
+			if !ok || i >= len(params) {
+				continue
+			}
 
-	ctx := context.Background()
+			value, err := def.paramType(i, params[i], field.Type)
+			if err != nil {
+				return reflect.Value{}, true, err
+			}
 
-	s.callBeforeScenarios(ctx)
-	defer s.callAfterScenarios(ctx)
+			if !value.IsValid() || !value.Type().AssignableTo(field.Type) {
+				return reflect.Value{}, true, fmt.Errorf("the field %s has unsupported type %s", field.Name, field.Type)
+			}
 
-	if bkg != nil {
-		for _, step := range bkg.Steps {
-			s.runStep(ctx, step)
+			v.FieldByIndex(field.Index).Set(value)
 		}
-	}
 
-	if len(scenario.Examples) > 0 {
-		steps := s.getOutlineStep(scenario.Steps, scenario.Examples)
-
-		ctx := context.Background()
-		for _, step := range steps {
-			s.runStep(ctx, step)
-		}
-		return
+		return v, true, nil
 	}
 
-	for _, step := range scenario.Steps {
-		s.runStep(ctx, step)
-	}
+	return reflect.Value{}, false, nil
 }
 
-func (s *Suite) runStep(ctx context.Context, step *msgs.Step) {
-	def, err := s.findStepDef(step.Text)
-	if err != nil {
-		panic(fmt.Sprintf("cannot find step definition for step: %s%s", step.Keyword, step.Text))
+// namedField finds an exported field of the struct for the named capturing group
+func namedField(structType reflect.Type, name string) (reflect.StructField, bool) {
+	if name == "" {
+		return reflect.StructField{}, false
 	}
 
-	params := def.expr.FindSubmatch([]byte(step.Text))[1:]
-
-	s.callBeforeSteps(ctx)
-	defer s.callAfterSteps(ctx)
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
 
-	def.run(ctx, params)
-}
+		if tag, ok := field.Tag.Lookup("gobdd"); ok {
+			if tag == name {
+				return field, true
+			}
 
-func (def *stepDef) run(ctx context.Context, params [][]byte) {
-	defer func() {
-		if r := recover(); r != nil {
-			// handle
+			continue
 		}
-	}()
 
-	d := reflect.ValueOf(def.f)
-	if len(params)+1 != d.Type().NumIn() {
-		panic(fmt.Sprintf("the step function %s accepts %d arguments but %d received", d.String(), d.Type().NumIn(), len(params)+1))
+		if strings.EqualFold(field.Name, name) {
+			return field, true
+		}
 	}
 
-	in := []reflect.Value{reflect.ValueOf(ctx)}
+	return reflect.StructField{}, false
+}
 
-	for i, v := range params {
-		if len(params) < i+1 {
-			break
+// paramType converts the i-th captured param, using the transform of its parameter type if there's any
+func (def *stepDef) paramType(i int, param []byte, inType reflect.Type) (reflect.Value, error) {
+	if names := def.expr.SubexpNames(); i+1 < len(names) {
+		if transform, ok := def.transforms[names[i+1]]; ok {
+			if transform.validation && def.skipValidation {
+				return reflect.ValueOf(string(param)), nil
+			}
+
+			value, err := transform.convert(string(param))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+
+			return reflect.ValueOf(value), nil
 		}
+	}
 
-		inType := d.Type().In(i + 1)
-		paramType := paramType(v, inType)
-		in = append(in, paramType)
+	if isListParamType(inType) {
+		return listParam(param, inType, def.listSeparator)
 	}
 
-	d.Call(in)
+	return paramType(param, inType)
 }
 
-func paramType(param []byte, inType reflect.Type) reflect.Value {
-	paramType := reflect.ValueOf(param)
-	if inType.Kind() == reflect.String {
-		paramType = reflect.ValueOf(string(paramType.Interface().([]uint8)))
+// listParam splits the captured param on the separator and converts every element, without surrounding whitespace,
+// to the element type of the slice. An empty param becomes an empty slice
+func listParam(param []byte, inType reflect.Type, sep string) (reflect.Value, error) {
+	list := reflect.MakeSlice(inType, 0, 0)
+	if strings.TrimSpace(string(param)) == "" {
+		return list, nil
 	}
 
-	if inType.Kind() == reflect.Int {
-		s := paramType.Interface().([]uint8)
-		p, _ := strconv.Atoi(string(s))
-		paramType = reflect.ValueOf(p)
-	}
+	for _, elem := range strings.Split(string(param), sep) {
+		value, err := paramType([]byte(strings.TrimSpace(elem)), inType.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		if !value.Type().AssignableTo(inType.Elem()) {
+			return reflect.Value{}, nil
+		}
 
-	if inType.Kind() == reflect.Float32 {
-		s := paramType.Interface().([]uint8)
-		p, _ := strconv.ParseFloat(string(s), 32)
-		paramType = reflect.ValueOf(float32(p))
+		list = reflect.Append(list, value)
 	}
 
-	if inType.Kind() == reflect.Float64 {
-		s := paramType.Interface().([]uint8)
-		p, _ := strconv.ParseFloat(string(s), 32)
-		paramType = reflect.ValueOf(p)
+	return list, nil
+}
+
+// paramType converts the captured param to the type of the argument of the step function.
+// It returns an error when the param cannot be converted, e.g. when it isn't a number
+func paramType(param []byte, inType reflect.Type) (reflect.Value, error) {
+	text := string(param)
+	value := reflect.ValueOf(param)
+
+	var err error
+	switch kind := inType.Kind(); {
+	case inType == durationType:
+		var d time.Duration
+		d, err = time.ParseDuration(text)
+		value = reflect.ValueOf(d)
+	case kind == reflect.String:
+		value = reflect.ValueOf(text)
+	case kind == reflect.Int:
+		var i int
+		i, err = strconv.Atoi(text)
+		value = reflect.ValueOf(i)
+	case kind == reflect.Int32, kind == reflect.Int64:
+		var i int64
+		i, err = strconv.ParseInt(text, 10, inType.Bits())
+		value = reflect.New(inType).Elem()
+		value.SetInt(i)
+	case kind == reflect.Uint, kind == reflect.Uint32, kind == reflect.Uint64:
+		var u uint64
+		u, err = strconv.ParseUint(text, 10, inType.Bits())
+		value = reflect.New(inType).Elem()
+		value.SetUint(u)
+	case kind == reflect.Float32:
+		var f float64
+		f, err = strconv.ParseFloat(text, 32)
+		value = reflect.ValueOf(float32(f))
+	case kind == reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(text, 64)
+		value = reflect.ValueOf(f)
+	case kind == reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(text)
+		value = reflect.ValueOf(b)
 	}
 
-	// add other types like boolean and StringOrInt
+	// add other types like StringOrInt
+
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("cannot convert captured value %q to %s", text, inType)
+	}
 
-	return paramType
+	return value, nil
 }
 
-func (s *Suite) findStepDef(text string) (stepDef, error) {
+// findStepDef returns the step definition matching the text of a step with the keyword.
+// When several definitions match the text, the one which takes precedence is chosen,
+// so the result doesn't depend on the order in which other definitions were added
+func (s *Suite) findStepDef(text string, keyword msgs.StepKeywordType) (stepDef, error) {
 	var sd stepDef
 
-	found := 0
 	matched := false
 
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	for _, step := range s.steps {
+		if step.keyword != "" && step.keyword != keyword {
+			continue
+		}
+
 		if !step.expr.MatchString(text) {
 			continue
 		}
-		matched = true
 
-		if l := len(step.expr.FindAll([]byte(text), -1)); l > found {
-			found = l
+		if !matched || step.takesPrecedence(sd) {
 			sd = step
 		}
+		matched = true
 	}
 
 	if !matched {
 		return sd, errors.New("cannot find step definition")
 	}
 
+	atomic.AddInt64(&sd.usage.matches, 1)
+
 	return sd, nil
 }
 
+// skipChild tells whether the scenario should be skipped because of its name or tags.
+// Scenarios inherit tags of their feature. A scenario outline is skipped when all of its examples are filtered out
+func (s *Suite) skipChild(featureTags []*msgs.Tag, scenario *msgs.Scenario) bool {
+	if s.options.nameFilter != nil && !s.options.nameFilter.MatchString(scenario.Name) {
+		return true
+	}
+
+	if len(scenario.Examples) == 0 {
+		return s.skipScenario(combineTags(featureTags, scenario.Tags))
+	}
+
+	return len(s.examplesToRun(featureTags, scenario)) == 0
+}
+
+// examplesToRun returns examples of the scenario outline matching the tag filters.
+// Tags of an examples block are combined with tags of the scenario and its feature
+func (s *Suite) examplesToRun(featureTags []*msgs.Tag, scenario *msgs.Scenario) []*msgs.Examples {
+	examples := []*msgs.Examples{}
+
+	for _, example := range scenario.Examples {
+		if !s.skipScenario(combineTags(featureTags, scenario.Tags, example.Tags)) {
+			examples = append(examples, example)
+		}
+	}
+
+	return examples
+}
+
+// combineTags returns tags of a feature, a scenario or examples together with tags they inherit
+func combineTags(tags ...[]*msgs.Tag) []*msgs.Tag {
+	combined := []*msgs.Tag{}
+	for _, t := range tags {
+		combined = append(combined, t...)
+	}
+
+	return combined
+}
+
+// skippedByTag tells whether the tags contain @skip, or @wip unless the suite is configured with WithWIPStrict
+func (s *Suite) skippedByTag(tags []*msgs.Tag) bool {
+	for _, tag := range tags {
+		if tag.Name == "@skip" || (tag.Name == "@wip" && !s.options.wipStrict) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// skipScenario tells whether a scenario with the tags should be skipped.
+// A scenario with any of the ignored tags is always skipped,
+// otherwise it has to match the tag expression and tags of the suite
 func (s *Suite) skipScenario(scenarioTags []*msgs.Tag) bool {
 	for _, tag := range scenarioTags {
 		if contains(s.options.ignoreTags, tag.Name) {
@@ -501,6 +2561,17 @@ func (s *Suite) skipScenario(scenarioTags []*msgs.Tag) bool {
 		}
 	}
 
+	if s.options.tagExpression != nil {
+		names := make([]string, 0, len(scenarioTags))
+		for _, tag := range scenarioTags {
+			names = append(names, tag.Name)
+		}
+
+		if !s.options.tagExpression.evaluate(names) {
+			return true
+		}
+	}
+
 	if len(s.options.tags) == 0 {
 		return false
 	}
@@ -525,16 +2596,12 @@ func contains(a []string, x string) bool {
 	return false
 }
 
-func getRegexpForVar(v interface{}) string {
-	s := v.(string)
-
-	if _, err := strconv.Atoi(s); err == nil {
-		return "(\\d+)"
-	}
-
-	if _, err := strconv.ParseFloat(s, 32); err == nil {
-		return "([+-]?([0-9]*[.])?[0-9]+)"
+// warn reports the message in the test's log or to the output of the suite if it isn't run within a test
+func (s *Suite) warn(t *testing.T, msg string) {
+	if t != nil {
+		t.Log(msg)
+		return
 	}
 
-	return "(.*)"
+	fmt.Fprintln(s.options.output, msg)
 }
@@ -1,80 +1,306 @@
 package gobdd
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"fmt"
+	"io"
+	"reflect"
 	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	msgs "github.com/cucumber/messages/go/v21"
 	"github.com/go-bdd/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/go-bdd/gobdd/models"
 )
 
 func TestScenarios(t *testing.T) {
-	suite := NewSuite(t, WithFeaturesPath("features/example.feature"))
+	suite := NewSuite(WithFeaturesPath("features/example.feature"))
 	compiled := regexp.MustCompile(`I add (\d+) and (\d+)`)
 	suite.AddRegexStep(compiled, add)
 	compiled = regexp.MustCompile(`the result should equal (\d+)`)
 	suite.AddRegexStep(compiled, check)
 
-	suite.Run()
+	suite.RunWithT(t)
 }
 
 func TestAddStepWithRegexp(t *testing.T) {
-	suite := NewSuite(t, WithFeaturesPath("features/example.feature"))
+	suite := NewSuite(WithFeaturesPath("features/example.feature"))
 	suite.AddStep(`I add (\d+) and (\d+)`, add)
 	suite.AddStep(`the result should equal (\d+)`, check)
 
-	suite.Run()
+	suite.RunWithT(t)
 }
 
 func TestDifferentFuncTypes(t *testing.T) {
-	suite := NewSuite(t, WithFeaturesPath("features/func_types.feature"))
+	suite := NewSuite(WithFeaturesPath("features/func_types.feature"))
 	suite.AddStep(`I add ([+-]?[0-9]*[.]?[0-9]+) and ([+-]?[0-9]*[.]?[0-9]+)`, addf)
 	suite.AddStep(`the result should equal ([+-]?[0-9]*[.]?[0-9]+)`, checkf)
 
-	suite.Run()
+	suite.RunWithT(t)
 }
 
 func TestScenarioOutline(t *testing.T) {
-	suite := NewSuite(t, WithFeaturesPath("features/outline.feature"))
+	suite := NewSuite(WithFeaturesPath("features/outline.feature"))
 	suite.AddStep(`I add (\d+) and (\d+)`, add)
 	suite.AddStep(`the result should equal (\d+)`, check)
 
-	suite.Run()
+	suite.RunWithT(t)
 }
 
 func TestParameterTypes(t *testing.T) {
-	suite := NewSuite(t, WithFeaturesPath("features/parameter-types.feature"))
+	suite := NewSuite(WithFeaturesPath("features/parameter-types.feature"))
 	suite.AddStep(`I add {int} and {int}`, add)
 	suite.AddStep(`the result should equal {int}`, check)
 	suite.AddStep(`I add floats {float} and {float}`, addf)
 	suite.AddStep(`the result should equal float {float}`, checkf)
-	suite.AddStep(`I use word {word}`, func(t StepTest, ctx Context, word string) {
+	suite.AddStep(`I use word {word}`, func(t StepTest, ctx context.Context, word string) {
 		if word != "pizza" {
 			t.Fatal("it should be pizza")
 		}
 	})
-	suite.AddStep(`I use text {text}`, func(t StepTest, ctx Context, text string) {
+	suite.AddStep(`I use text {text}`, func(t StepTest, ctx context.Context, text string) {
 		if text != "I like pizza" {
 			t.Fatal("it should say that I like pizza")
 		}
 	})
+	suite.AddStep(`I wait {duration}`, func(t StepTest, ctx context.Context, d time.Duration) {
+		if d != 1500*time.Millisecond {
+			t.Fatalf("it should be 1500ms but got %s", d)
+		}
+	})
+	suite.AddStep(`the log says {anything}`, func(t StepTest, ctx context.Context, message string) {
+		if message != "error: disk full (code 5)" {
+			t.Fatalf("it should be the whole message but got %q", message)
+		}
+	})
 
-	suite.Run()
+	suite.RunWithT(t)
+}
+
+func TestEmailAndURLParameterTypes(t *testing.T) {
+	newSuite := func(options ...func(*SuiteOptions)) (*Suite, *[]string) {
+		captured := []string{}
+		suite := NewSuite(append(options, WithOutput(io.Discard), WithInlineFeature("web.feature", `Feature: web
+  Scenario: valid email
+    Given the email is john.doe+news@example.com
+  Scenario: invalid email
+    Given the email is john..doe@example.com
+  Scenario: valid URL
+    Given the page is https://example.com/search?q=pizza
+  Scenario: invalid URL
+    Given the page is http://%zz
+  Scenario: URL without host
+    Given the page is http://
+`))...)
+		suite.AddStep(`the email is {email}`, func(ctx context.Context, email string) {
+			captured = append(captured, email)
+		})
+		suite.AddStep(`the page is {url}`, func(ctx context.Context, url string) {
+			captured = append(captured, url)
+		})
+
+		return suite, &captured
+	}
+
+	suite, captured := newSuite()
+	scenarios, err := suite.RunCollect()
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"john.doe+news@example.com", "https://example.com/search?q=pizza"}, *captured)
+	require.Equal(t, models.Passed, scenarios[0].Result)
+	require.Equal(t, models.Failed, scenarios[1].Result)
+	require.Contains(t, scenarios[1].Steps[0].Err.Error(), "john..doe@example.com isn't a valid email")
+	require.Equal(t, models.Passed, scenarios[2].Result)
+	require.Equal(t, models.Failed, scenarios[3].Result)
+	require.Contains(t, scenarios[3].Steps[0].Err.Error(), "http://%zz isn't a valid URL")
+	require.Equal(t, models.Failed, scenarios[4].Result)
+	require.Contains(t, scenarios[4].Steps[0].Err.Error(), "the host is missing")
+
+	withoutValidation := map[string]func() (*Suite, *[]string){
+		"new suite": func() (*Suite, *[]string) {
+			return newSuite(WithoutParameterValidation())
+		},
+		"clone": func() (*Suite, *[]string) {
+			suite, captured := newSuite()
+
+			return suite.Clone(WithoutParameterValidation()), captured
+		},
+		"clone of a suite without validation": func() (*Suite, *[]string) {
+			suite, captured := newSuite(WithoutParameterValidation())
+
+			return suite.Clone(), captured
+		},
+	}
+
+	for name, create := range withoutValidation {
+		t.Run(name, func(t *testing.T) {
+			suite, captured := create()
+			scenarios, err := suite.RunCollect()
+
+			require.NoError(t, err)
+			require.Len(t, *captured, 5)
+			for _, scenario := range scenarios {
+				require.Equal(t, models.Passed, scenario.Result)
+			}
+		})
+	}
+}
+
+func TestWithoutParameterValidationOfRegexSteps(t *testing.T) {
+	expr := regexp.MustCompile(fmt.Sprintf(`the email is (?P<%s>\S+)`, transformGroupName(`{email}`)))
+	feature := WithInlineFeature("email.feature", `Feature: email
+  Scenario: invalid email
+    Given the email is john..doe@example.com
+`)
+
+	testCases := map[string]struct {
+		options  []func(*SuiteOptions)
+		expected models.Result
+	}{
+		"with validation":    {expected: models.Failed},
+		"without validation": {options: []func(*SuiteOptions){WithoutParameterValidation()}, expected: models.Passed},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			suite := NewSuite(append(testCase.options, feature, WithOutput(io.Discard))...)
+			suite.AddRegexStep(expr, func(ctx context.Context, email string) {})
+
+			scenarios, err := suite.RunCollect()
+
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, scenarios[0].Result)
+		})
+	}
+}
+
+func TestParamTypeIntegers(t *testing.T) {
+	testCases := map[string]struct {
+		param    string
+		inType   reflect.Type
+		expected interface{}
+	}{
+		"int":    {param: "-42", inType: reflect.TypeOf(int(0)), expected: int(-42)},
+		"int32":  {param: "-2147483648", inType: reflect.TypeOf(int32(0)), expected: int32(-2147483648)},
+		"int64":  {param: "9223372036854775807", inType: reflect.TypeOf(int64(0)), expected: int64(9223372036854775807)},
+		"uint":   {param: "42", inType: reflect.TypeOf(uint(0)), expected: uint(42)},
+		"uint32": {param: "4294967295", inType: reflect.TypeOf(uint32(0)), expected: uint32(4294967295)},
+		"uint64": {param: "18446744073709551615", inType: reflect.TypeOf(uint64(0)), expected: uint64(18446744073709551615)},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			value, err := paramType([]byte(testCase.param), testCase.inType)
+
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, value.Interface())
+		})
+	}
+}
+
+func TestArgumentConversionErrorFailsStep(t *testing.T) {
+	called := false
+	suite := NewSuite(WithOutput(io.Discard), WithInlineFeature("apples.feature", `Feature: apples
+  Scenario: counting apples
+    Given I have many apples
+`))
+	suite.AddStep(`I have (\w+) apples`, func(ctx context.Context, count int) {
+		called = true
+	})
+
+	scenarios, err := suite.RunCollect()
+
+	require.NoError(t, err)
+	require.False(t, called)
+	require.Equal(t, models.Failed, scenarios[0].Steps[0].Result)
+	require.Contains(t, scenarios[0].Steps[0].Err.Error(), `cannot convert captured value "many" to int`)
+}
+
+func TestListArguments(t *testing.T) {
+	fsys := fstest.MapFS{
+		"lists.feature": {Data: []byte(`Feature: lists
+  Scenario: lists
+    Given the numbers are 1, 2, 3
+    And the tags are a | b c | d
+    And the weights are 1.5,2.25
+`)},
+		"pipes.feature": {Data: []byte(`Feature: lists
+  Scenario: lists separated by pipes
+    Given the tags are a | b c | d
+`)},
+	}
+
+	var numbers []int
+	var tags []string
+	var weights []float64
+	newSuite := func(path string, options ...func(*SuiteOptions)) *Suite {
+		suite := NewSuite(append(options, WithFeaturesFS(fsys, path))...)
+		suite.AddStep(`the numbers are (.*)`, func(ctx context.Context, n []int) {
+			numbers = n
+		})
+		suite.AddStep(`the tags are (.*)`, func(ctx context.Context, t []string) {
+			tags = t
+		})
+		suite.AddStep(`the weights are (.*)`, func(ctx context.Context, w []float64) {
+			weights = w
+		})
+
+		return suite
+	}
+
+	newSuite("lists.feature").RunWithT(t)
+
+	require.Equal(t, []int{1, 2, 3}, numbers)
+	require.Equal(t, []string{"a | b c | d"}, tags)
+	require.Equal(t, []float64{1.5, 2.25}, weights)
+
+	newSuite("pipes.feature", WithListSeparator("|")).RunWithT(t)
+
+	require.Equal(t, []string{"a", "b c", "d"}, tags)
+}
+
+func TestListArgumentsOfRegexSteps(t *testing.T) {
+	var numbers []int
+	suite := NewSuite(WithInlineFeature("lists.feature", `Feature: lists
+  Scenario: lists
+    Given the numbers are 1, 2, 3
+`))
+	suite.AddRegexStep(regexp.MustCompile(`the numbers are (.*)`), func(ctx context.Context, n []int) {
+		numbers = n
+	})
+
+	suite.RunWithT(t)
+
+	require.Equal(t, []int{1, 2, 3}, numbers)
+}
+
+func TestWithListSeparatorPanicsWhenEmpty(t *testing.T) {
+	require.Panics(t, func() {
+		NewSuite(WithListSeparator(""))
+	})
 }
 
 func TestScenarioOutlineExecutesAllTests(t *testing.T) {
 	c := 0
-	suite := NewSuite(t, WithFeaturesPath("features/outline.feature"))
+	suite := NewSuite(WithFeaturesPath("features/outline.feature"))
 	suite.AddStep(`I add (\d+) and (\d+)`, add)
-	suite.AddStep(`the result should equal (\d+)`, func(t StepTest, ctx Context, sum int) {
+	suite.AddStep(`the result should equal (\d+)`, func(t StepTest, ctx context.Context, sum int) {
 		c++
 		check(t, ctx, sum)
 	})
 
-	suite.Run()
+	suite.RunWithT(t)
 
 	if err := assert.Equals(2, c); err != nil {
 		t.Errorf("expected to run %d times but %d got", 2, c)
@@ -82,48 +308,153 @@ func TestScenarioOutlineExecutesAllTests(t *testing.T) {
 }
 
 func TestStepFromExample(t *testing.T) {
-	s := NewSuite(t)
-	st, expr := s.stepFromExample("I add <d1> and <d2>", &msgs.GherkinDocument_Feature_TableRow{
-		Cells: []*msgs.GherkinDocument_Feature_TableRow_TableCell{
+	s := NewSuite()
+	st := s.stepFromExample("I add <d1> and <d2>", &msgs.TableRow{
+		Cells: []*msgs.TableCell{
 			{Value: "1"},
 			{Value: "2"},
 		},
 	}, []string{"<d1>", "<d2>"})
 
-	if err := assert.NotNil(st); err != nil {
+	if err := assert.Equals("I add 1 and 2", st); err != nil {
 		t.Error(err)
 	}
+}
 
-	if err := assert.Equals("I add 1 and 2", st); err != nil {
-		t.Error(err)
+func TestStepFromExampleWithPlaceholderValues(t *testing.T) {
+	s := NewSuite()
+	st := s.stepFromExample("I type <value> (twice) at <rate>", &msgs.TableRow{
+		Cells: []*msgs.TableCell{
+			{Value: "a.b(<rate>)"},
+			{Value: "1.5"},
+		},
+	}, []string{"<value>", "<rate>"})
+
+	require.Equal(t, "I type a.b(<rate>) (twice) at 1.5", st)
+}
+
+func TestWithInlineFeature(t *testing.T) {
+	executed := []string{}
+	suite := NewSuite(WithInlineFeature("inline.feature", `Feature: inline
+  Scenario: inline scenario
+    When the "inline" scenario runs
+`))
+	suite.AddStep(`the "(.*)" scenario runs`, func(_ context.Context, name string) {
+		executed = append(executed, name)
+	})
+
+	suite.RunWithT(t)
+
+	require.Equal(t, []string{"inline"}, executed)
+	require.Equal(t, "inline.feature", suite.results[0].Uri)
+}
+
+func TestOutlineDoesNotAddSteps(t *testing.T) {
+	fsys := fstest.MapFS{
+		"outline.feature": {Data: []byte(`Feature: outline
+  Scenario Outline: adding numbers
+    When I add <a> and <b>
+    Then the result should equal <sum>
+    Examples:
+      | a | b | sum |
+      | 1 | 2 | 3   |
+      | 5 | 5 | 10  |
+      | 7 | 0 | 7   |
+`)},
 	}
 
-	if err := assert.Equals(`I add (\d+) and (\d+)`, expr); err != nil {
-		t.Error(err)
+	suite := NewSuite(WithFeaturesFS(fsys, "outline.feature"))
+	suite.AddStep(`I add (\d+) and (\d+)`, add)
+	suite.AddStep(`the result should equal (\d+)`, check)
+	steps := len(suite.steps)
+
+	suite.RunWithT(t)
+	suite.RunWithT(t)
+
+	require.Len(t, suite.steps, steps)
+	require.Empty(t, suite.UnusedSteps())
+}
+
+func TestOutlineExamplesReportedSeparately(t *testing.T) {
+	fsys := fstest.MapFS{
+		"outline.feature": {Data: []byte(`Feature: outline
+  Scenario Outline: Login
+    When I add <a> and <b>
+    Then the result should equal <sum>
+    Examples:
+      | a | b | sum |
+      | 1 | 2 | 3   |
+      | 5 | 5 | 11  |
+      | 7 | 0 | 7   |
+`)},
+	}
+
+	suite := NewSuite(WithFeaturesFS(fsys, "outline.feature"), WithOutput(io.Discard))
+	suite.AddStep(`I add (\d+) and (\d+)`, add)
+	suite.AddStep(`the result should equal (\d+)`, check)
+
+	require.NoError(t, suite.Run())
+
+	names := []string{}
+	results := []models.Result{}
+	for _, scenario := range suite.results[0].Scenarios {
+		names = append(names, scenario.Name)
+		results = append(results, scenario.Result())
+	}
+
+	require.Equal(t, []string{
+		"Login example #1 (1, 2, 3)",
+		"Login example #2 (5, 5, 11)",
+		"Login example #3 (7, 0, 7)",
+	}, names)
+	require.Equal(t, []models.Result{models.Passed, models.Failed, models.Passed}, results)
+	require.Equal(t, int64(8), suite.results[0].Scenarios[1].Location.Line)
+}
+
+func TestOutlineWithSpecialCharacters(t *testing.T) {
+	fsys := fstest.MapFS{
+		"special.feature": {Data: []byte(`Feature: special characters
+  Scenario Outline: typing
+    When I type <value> (twice)
+    Examples:
+      | value  |
+      | a.b(c) |
+      | [x]*   |
+`)},
 	}
+
+	typed := []string{}
+	suite := NewSuite(WithFeaturesFS(fsys, "special.feature"))
+	suite.AddStep(`I type (.+) \(twice\)`, func(ctx context.Context, value string) {
+		typed = append(typed, value)
+	})
+
+	suite.RunWithT(t)
+
+	require.Equal(t, []string{"a.b(c)", "[x]*"}, typed)
 }
 
 func TestBackground(t *testing.T) {
-	suite := NewSuite(t, WithFeaturesPath("features/background.feature"))
+	suite := NewSuite(WithFeaturesPath("features/background.feature"))
 	suite.AddStep(`I add (\d+) and (\d+)`, add)
 	suite.AddStep(`the result should equal (\d+)`, check)
 
-	suite.Run()
+	suite.RunWithT(t)
 }
 
 func TestTags(t *testing.T) {
-	suite := NewSuite(t, WithFeaturesPath("features/tags.feature"), WithTags("@tag"))
+	suite := NewSuite(WithFeaturesPath("features/tags.feature"), WithTags("@tag"))
 	suite.AddStep(`fail the test`, fail)
 	suite.AddStep(`the test should pass`, pass)
 
-	suite.Run()
+	suite.RunWithT(t)
 }
 
 func TestFilterFeatureWithTags(t *testing.T) {
-	suite := NewSuite(t, WithFeaturesPath("features/filter_tags_*.feature"), WithTags("@run-this"))
+	suite := NewSuite(WithFeaturesPath("features/filter_tags_*.feature"), WithTags("@run-this"))
 	c := false
 
-	suite.AddStep(`the test should pass`, func(_ StepTest, _ Context) {
+	suite.AddStep(`the test should pass`, func(_ StepTest, _ context.Context) {
 		c = true
 	})
 	suite.AddStep(`fail the test`, fail)
@@ -135,12 +466,141 @@ func TestFilterFeatureWithTags(t *testing.T) {
 	}
 }
 
+func TestFeatureTagsInheritedByScenarios(t *testing.T) {
+	fsys := fstest.MapFS{
+		"smoke.feature": {Data: []byte(`@smoke
+Feature: smoke tests
+  Scenario: untagged smoke scenario
+    When the "smoke" scenario runs
+
+  @slow
+  Scenario: slow smoke scenario
+    When the "slow smoke" scenario runs
+`)},
+		"other.feature": {Data: []byte(`Feature: other tests
+  Scenario: untagged scenario
+    When the "other" scenario runs
+`)},
+	}
+
+	testCases := map[string]struct {
+		option   func(*SuiteOptions)
+		expected []string
+	}{
+		"tags":           {option: WithTags("@smoke"), expected: []string{"smoke", "slow smoke"}},
+		"tag expression": {option: WithTagExpression("@smoke and not @slow"), expected: []string{"smoke"}},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			executed := []string{}
+			suite := NewSuite(WithFeaturesFS(fsys, "*.feature"), testCase.option)
+			suite.AddStep(`the "(.*)" scenario runs`, func(_ context.Context, name string) {
+				executed = append(executed, name)
+			})
+
+			suite.RunWithT(t)
+
+			require.ElementsMatch(t, testCase.expected, executed)
+		})
+	}
+}
+
+func TestTagExpressions(t *testing.T) {
+	testCases := map[string][]string{
+		"@smoke and @fast":      {"smoke and fast"},
+		"@smoke or @slow":       {"smoke and fast", "smoke and wip", "slow"},
+		"not @smoke":            {"slow", "untagged"},
+		"@smoke and (not @wip)": {"smoke and fast"},
+		"not (@smoke or @slow)": {"untagged"},
+	}
+
+	for expr, expected := range testCases {
+		t.Run(expr, func(t *testing.T) {
+			executed := []string{}
+			suite := NewSuite(WithFeaturesPath("features/tag_expressions.feature"), WithTagExpression(expr), WithWIPStrict())
+			suite.AddStep(`the "(.*)" scenario runs`, func(_ StepTest, _ context.Context, name string) {
+				executed = append(executed, name)
+			})
+
+			suite.RunWithT(t)
+
+			require.Equal(t, expected, executed)
+		})
+	}
+}
+
+func TestWithNameFilter(t *testing.T) {
+	testCases := map[string]struct {
+		options  []func(*SuiteOptions)
+		expected []string
+	}{
+		"single scenario": {
+			options:  []func(*SuiteOptions){WithFeaturesPath("features/tag_expressions.feature"), WithNameFilter(`^slow$`)},
+			expected: []string{"slow"},
+		},
+		"combined with tags": {
+			options: []func(*SuiteOptions){
+				WithFeaturesPath("features/tag_expressions.feature"), WithNameFilter(`smoke`), WithTags("@wip"), WithWIPStrict(),
+			},
+			expected: []string{"smoke and wip"},
+		},
+		"scenario outline": {
+			options:  []func(*SuiteOptions){WithFeaturesPath("features/lines.feature"), WithNameFilter(`outline`)},
+			expected: []string{"third", "fourth"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			executed := []string{}
+			suite := NewSuite(testCase.options...)
+			suite.AddStep(`the "(.*)" scenario runs`, func(_ context.Context, name string) {
+				executed = append(executed, name)
+			})
+
+			suite.RunWithT(t)
+
+			require.Equal(t, testCase.expected, executed)
+		})
+	}
+}
+
+func TestWithNameFilterPanicsOnInvalidPattern(t *testing.T) {
+	require.Panics(t, func() {
+		NewSuite(WithNameFilter(`(`))
+	})
+}
+
+func TestWithFeatureHooks(t *testing.T) {
+	before, after, scenarios := 0, 0, 0
+	suite := NewSuite(
+		WithFeaturesPath("features/tag_expressions.feature"),
+		WithWIPStrict(),
+		WithBeforeFeature(func(ctx context.Context) {
+			before++
+		}),
+		WithAfterFeature(func(ctx context.Context) {
+			after++
+		}),
+	)
+	suite.AddStep(`the "(.*)" scenario runs`, func(_ StepTest, _ context.Context, _ string) {
+		scenarios++
+	})
+
+	suite.RunWithT(t)
+
+	require.Equal(t, 4, scenarios)
+	require.Equal(t, 1, before)
+	require.Equal(t, 1, after)
+}
+
 func TestWithAfterScenario(t *testing.T) {
 	c := false
-	suite := NewSuite(t, WithFeaturesPath("features/empty.feature"), WithAfterScenario(func(ctx Context) {
+	suite := NewSuite(WithFeaturesPath("features/empty.feature"), WithAfterScenario(func(ctx context.Context) {
 		c = true
 	}))
-	suite.Run()
+	suite.RunWithT(t)
 
 	if err := assert.Equals(true, c); err != nil {
 		t.Error(err)
@@ -149,10 +609,10 @@ func TestWithAfterScenario(t *testing.T) {
 
 func TestWithBeforeScenario(t *testing.T) {
 	c := false
-	suite := NewSuite(t, WithFeaturesPath("features/empty.feature"), WithBeforeScenario(func(ctx Context) {
+	suite := NewSuite(WithFeaturesPath("features/empty.feature"), WithBeforeScenario(func(ctx context.Context) {
 		c = true
 	}))
-	suite.Run()
+	suite.RunWithT(t)
 
 	if err := assert.Equals(true, c); err != nil {
 		t.Error(err)
@@ -161,42 +621,82 @@ func TestWithBeforeScenario(t *testing.T) {
 
 func TestWithAfterStep(t *testing.T) {
 	c := 0
-	suite := NewSuite(t, WithFeaturesPath("features/background.feature"), WithAfterStep(func(ctx Context) {
+	suite := NewSuite(WithFeaturesPath("features/background.feature"), WithAfterStep(func(ctx context.Context) {
 		c++
 
-		// feature should be *msgs.GherkinDocument_Feature
-		feature, err := ctx.Get(FeatureKey{})
-		require.NoError(t, err)
-		if _, ok := feature.(*msgs.GherkinDocument_Feature); !ok {
-			t.Errorf("expected feature but got %T", feature)
+		if uri := featureURIFromContext(ctx); uri != "features/background.feature" {
+			t.Errorf("expected the feature but got %q", uri)
 		}
 
-		// scenario should be *msgs.GherkinDocument_Feature_Scenario
-		scenario, err := ctx.Get(ScenarioKey{})
-		require.NoError(t, err)
-		if _, ok := scenario.(*msgs.GherkinDocument_Feature_Scenario); !ok {
-			t.Errorf("expected scenario but got %T", scenario)
+		if name := ScenarioName(ctx); name != "the background step should be executed" {
+			t.Errorf("expected the scenario name but got %q", name)
 		}
 	}))
 	suite.AddStep(`I add (\d+) and (\d+)`, add)
 	suite.AddStep(`the result should equal (\d+)`, check)
 
-	suite.Run()
+	suite.RunWithT(t)
 
 	if err := assert.Equals(2, c); err != nil {
 		t.Error(err)
 	}
 }
 
+func TestWithScenarioHooksTagged(t *testing.T) {
+	before, after := []string{}, []string{}
+	suite := NewSuite(
+		WithFeaturesPath("features/tag_expressions.feature"),
+		WithWIPStrict(),
+		WithBeforeScenarioTagged("@smoke", func(ctx context.Context) {
+			before = append(before, "smoke")
+		}),
+		WithAfterScenarioTagged("@slow", func(ctx context.Context) {
+			after = append(after, "slow")
+		}),
+	)
+	suite.AddStep(`the ".*" scenario runs`, pass)
+
+	suite.RunWithT(t)
+
+	require.Equal(t, []string{"smoke", "smoke"}, before)
+	require.Equal(t, []string{"slow"}, after)
+}
+
+func TestScenarioMetadata(t *testing.T) {
+	var hookName, stepName string
+	var stepTags []string
+	var stepLocation *msgs.Location
+
+	suite := NewSuite(
+		WithFeaturesPath("features/tag_expressions.feature"),
+		WithTags("@slow"),
+		WithBeforeScenario(func(ctx context.Context) {
+			hookName = ScenarioName(ctx)
+		}),
+	)
+	suite.AddStep(`the ".*" scenario runs`, func(_ StepTest, ctx context.Context) {
+		stepName = ScenarioName(ctx)
+		stepTags = ScenarioTags(ctx)
+		stepLocation = ScenarioLocation(ctx)
+	})
+
+	suite.RunWithT(t)
+
+	require.Equal(t, "slow", hookName)
+	require.Equal(t, "slow", stepName)
+	require.Equal(t, []string{"@slow"}, stepTags)
+	require.Equal(t, int64(9), stepLocation.Line)
+}
+
 func TestWithBeforeStep(t *testing.T) {
 	c := 0
-	suite := NewSuite(t, WithFeaturesPath("features/background.feature"), WithBeforeStep(func(ctx Context) {
+	suite := NewSuite(WithFeaturesPath("features/background.feature"), WithBeforeStep(func(ctx context.Context) {
 		c++
 	}))
 	suite.AddStep(`I add (\d+) and (\d+)`, add)
 	suite.AddStep(`the result should equal (\d+)`, check)
 
-	suite.Run()
+	suite.RunWithT(t)
 
 	if err := assert.Equals(2, c); err != nil {
 		t.Error(err)
@@ -204,15 +704,72 @@ func TestWithBeforeStep(t *testing.T) {
 }
 
 func TestIgnoredTags(t *testing.T) {
-	suite := NewSuite(t, WithFeaturesPath("features/ignored_tags.feature"), WithIgnoredTags("@ignore"))
+	suite := NewSuite(WithFeaturesPath("features/ignored_tags.feature"), WithIgnoredTags("@ignore"))
 	suite.AddStep(`fail the test`, fail)
-	suite.Run()
+	suite.RunWithT(t)
 }
 
 func TestIgnorFeatureWithTags(t *testing.T) {
-	suite := NewSuite(t, WithFeaturesPath("features/ignored_feature_tags.feature"), WithIgnoredTags("@ignore"))
+	suite := NewSuite(WithFeaturesPath("features/ignored_feature_tags.feature"), WithIgnoredTags("@ignore"))
 	suite.AddStep(`fail the test`, fail)
-	suite.Run()
+	suite.RunWithT(t)
+}
+
+func TestIgnoredTagsTakePrecedence(t *testing.T) {
+	fsys := fstest.MapFS{
+		"precedence.feature": {Data: []byte(`Feature: tag precedence
+  @wip @smoke
+  Scenario: ignored and required
+    When the "ignored and required" scenario runs
+
+  @wip
+  Scenario: ignored
+    When the "ignored" scenario runs
+
+  @smoke
+  Scenario: required
+    When the "required" scenario runs
+
+  Scenario: untagged
+    When the "untagged" scenario runs
+`)},
+	}
+
+	testCases := map[string]struct {
+		options  []func(*SuiteOptions)
+		expected []string
+	}{
+		"ignored and required tags": {
+			options:  []func(*SuiteOptions){WithIgnoredTags("@wip"), WithTags("@smoke")},
+			expected: []string{"required"},
+		},
+		"required tags passed first": {
+			options:  []func(*SuiteOptions){WithTags("@smoke"), WithIgnoredTags("@wip")},
+			expected: []string{"required"},
+		},
+		"ignored tags only": {
+			options:  []func(*SuiteOptions){WithIgnoredTags("@wip")},
+			expected: []string{"required", "untagged"},
+		},
+		"tag expression": {
+			options:  []func(*SuiteOptions){WithIgnoredTags("@wip"), WithTagExpression("@smoke or @wip")},
+			expected: []string{"required"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			executed := []string{}
+			suite := NewSuite(append(testCase.options, WithFeaturesFS(fsys, "precedence.feature"))...)
+			suite.AddStep(`the "(.*)" scenario runs`, func(_ context.Context, name string) {
+				executed = append(executed, name)
+			})
+
+			suite.RunWithT(t)
+
+			require.Equal(t, testCase.expected, executed)
+		})
+	}
 }
 
 func TestInvalidFunctionSignature(t *testing.T) {
@@ -220,26 +777,122 @@ func TestInvalidFunctionSignature(t *testing.T) {
 		f interface{}
 	}{
 		"nil":                              {},
-		"func without return value":        {f: func(ctx Context) {}},
-		"func with invalid return value":   {f: func(ctx Context) int { return 0 }},
+		"func with invalid return value":   {f: func(ctx context.Context) int { return 0 }},
 		"func without arguments":           {f: func() error { return errors.New("") }},
 		"func with invalid first argument": {f: func(i int) error { return errors.New("") }},
 	}
 
 	for name, testCase := range testCases {
 		t.Run(name, func(t *testing.T) {
-			tester := &mockTester{}
-			suite := NewSuite(tester)
-			suite.AddStep("", testCase.f)
-			suite.Run()
-			err := assert.Equals(1, tester.fatalCalled)
-			if err != nil {
-				t.Fatal(err)
-			}
+			suite := NewSuite()
+			require.Panics(t, func() {
+				suite.AddStep("", testCase.f)
+			})
+		})
+	}
+}
+
+func TestStepArgumentsMismatch(t *testing.T) {
+	testCases := map[string]struct {
+		expr     string
+		f        interface{}
+		expected string
+	}{
+		"too few capturing groups": {
+			expr: `I add (\d+) and 2`,
+			f:    func(_ context.Context, _, _ int) {},
+			expected: "the step function for step `I add (\\d+) and 2` is incorrect: " +
+				"the expression I add (\\d+) and 2 has 1 capturing groups but the function accepts 2 arguments",
+		},
+		"too many capturing groups": {
+			expr: `I add {int} and {int}`,
+			f:    func(_ StepTest, _ context.Context, _ int) {},
+			expected: "the step function for step `I add {int} and {int}` is incorrect: " +
+				"the expression I add (-?\\d+) and (-?\\d+) has 2 capturing groups but the function accepts 1 arguments",
+		},
+		"unsupported type": {
+			expr: `the user {word}`,
+			f:    func(_ context.Context, _ struct{ Name string }) {},
+			expected: "the step function for step `the user {word}` is incorrect: " +
+				"the argument 1 of the function has unsupported type struct { Name string }",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			suite := NewSuite()
+			require.PanicsWithValue(t, testCase.expected, func() {
+				suite.AddStep(testCase.expr, testCase.f)
+			})
 		})
 	}
 }
 
+func TestStepWithDifferentParameterTypes(t *testing.T) {
+	var number int
+	var word string
+
+	suite := NewSuite(WithFeaturesPath("features/mixed_parameter_types.feature"))
+	suite.AddStep(`I have {int} {word}`, func(_ context.Context, n int, w string) {
+		number, word = n, w
+	})
+
+	suite.RunWithT(t)
+
+	require.Equal(t, 3, number)
+	require.Equal(t, "apples", word)
+}
+
+func TestTextParameterType(t *testing.T) {
+	fsys := fstest.MapFS{
+		"text.feature": {Data: []byte(`Feature: text parameter type
+  Scenario: quoted texts
+    Given the message is "It's broken!"
+    And the message is 'Hello, world: 1 + 1 = 2?'
+    And the message is "She said \"hello\" \\ left"
+    And the message is 'It\'s "fine"'
+    And the message is ""
+`)},
+	}
+
+	messages := []string{}
+	suite := NewSuite(WithFeaturesFS(fsys, "text.feature"))
+	suite.AddStep(`the message is {text}`, func(message string) {
+		messages = append(messages, message)
+	})
+
+	suite.RunWithT(t)
+
+	require.Equal(t, []string{
+		"It's broken!",
+		"Hello, world: 1 + 1 = 2?",
+		`She said "hello" \ left`,
+		`It's "fine"`,
+		"",
+	}, messages)
+}
+
+func TestStringParameterType(t *testing.T) {
+	fsys := fstest.MapFS{
+		"string.feature": {Data: []byte(`Feature: string parameter type
+  Scenario: double-quoted strings
+    Given the name is ""
+    And the name is "John Smith"
+    And the name is "O'Brien & Sons, Ltd. (#1)!"
+`)},
+	}
+
+	names := []string{}
+	suite := NewSuite(WithFeaturesFS(fsys, "string.feature"))
+	suite.AddStep(`the name is {string}`, func(name string) {
+		names = append(names, name)
+	})
+
+	suite.RunWithT(t)
+
+	require.Equal(t, []string{"", "John Smith", "O'Brien & Sons, Ltd. (#1)!"}, names)
+}
+
 func TestFailureOutput(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -256,7 +909,7 @@ func TestFailureOutput(t *testing.T) {
 			def := stepDef{f: testCase.f}
 
 			tester := &mockTester{}
-			def.run(NewContext(), tester, nil)
+			def.run(context.Background(), tester, &msgs.Step{}, nil)
 			err := assert.Equals(testCase.expectedErrors, tester.errors)
 			if err != nil {
 				t.Fatal(err)
@@ -265,54 +918,2042 @@ func TestFailureOutput(t *testing.T) {
 	}
 }
 
-func addf(_ StepTest, ctx Context, var1, var2 float32) {
-	res := var1 + var2
-	ctx.Set("sumRes", res)
-}
-
-func add(_ StepTest, ctx Context, var1, var2 int) {
-	res := var1 + var2
-	ctx.Set("sumRes", res)
-}
-
-func checkf(t StepTest, ctx Context, sum float32) {
-	received, err := ctx.Get("sumRes")
-	if err != nil {
-		t.Error(err.Error())
+func TestFailedStepIsReportedWithLocation(t *testing.T) {
+	suite := NewSuite()
+	suite.AddStep(`the step fails`, failure)
+	suite.AddStep(`the step passes`, pass)
 
-		return
-	}
+	tester := &mockTester{}
+	suite.runScenario(tester, &models.Feature{}, &msgs.Scenario{
+		Steps: []*msgs.Step{
+			{Keyword: "When ", Text: "the step passes", Location: &msgs.Location{Line: 3}},
+			{Keyword: "Then ", Text: "the step fails", Location: &msgs.Location{Line: 4}},
+		},
+	}, nil)
 
-	if sum != received {
-		t.Error("the sum doesn't match")
-	}
+	require.Equal(t, []string{"Then the step fails (line 4): the step failed"}, tester.errors)
 }
 
-func check(t StepTest, ctx Context, sum int) {
-	received, err := ctx.Get("sumRes")
-	if err != nil {
-		t.Error(err)
-		return
-	}
+func TestFailedStepIsReportedWithFeatureFile(t *testing.T) {
+	suite := NewSuite()
+	suite.AddStep(`the step panics`, func(ctx context.Context) {
+		panic("the step panicked")
+	})
 
-	if sum != received {
-		t.Errorf("expected %d but %d received", sum, received)
-	}
-}
+	tester := &mockTester{}
+	suite.runScenario(tester, &models.Feature{Uri: "features/login.feature"}, &msgs.Scenario{
+		Steps: []*msgs.Step{
+			{Keyword: "When ", Text: "the step panics", Location: &msgs.Location{Line: 12}},
+		},
+	}, nil)
 
-func fail(t StepTest, _ Context) {
-	t.Error("the step should never be executed")
+	require.Equal(t, []string{"When the step panics (features/login.feature:12): the step panicked"}, tester.errors)
 }
 
-func failure(t StepTest, _ Context) {
-	t.Error("the step failed")
-}
+func TestOutlineWithBackground(t *testing.T) {
+	type counterKey struct{}
 
-func panics(_ StepTest, _ Context) {
+	backgroundRuns := 0
+	suite := NewSuite(WithFeaturesPath("features/outline_background.feature"))
+	suite.AddStep(`the counter is set to (\d+)`, func(ctx context.Context, value int) context.Context {
+		backgroundRuns++
+		return context.WithValue(ctx, counterKey{}, value)
+	})
+	suite.AddStep(`I add (\d+) to the counter`, func(t StepTest, ctx context.Context, value int) context.Context {
+		counter, ok := ctx.Value(counterKey{}).(int)
+		if !ok {
+			t.Fatal("the counter is not set by the background")
+		}
+
+		return context.WithValue(ctx, counterKey{}, counter+value)
+	})
+	suite.AddStep(`the counter equals (\d+)`, func(t StepTest, ctx context.Context, expected int) {
+		if counter := ctx.Value(counterKey{}); counter != expected {
+			t.Errorf("expected the counter to equal %d but got %v", expected, counter)
+		}
+	})
+
+	suite.RunWithT(t)
+
+	require.Equal(t, 2, backgroundRuns)
+}
+
+func TestOutlineWithTaggedExamples(t *testing.T) {
+	checked := []int{}
+	suite := NewSuite(WithFeaturesPath("features/outline_tagged_examples.feature"), WithTags("@negative"))
+	suite.AddStep(`the number {int} is checked`, func(_ context.Context, number int) {
+		checked = append(checked, number)
+	})
+
+	suite.RunWithT(t)
+
+	require.Equal(t, []int{-1, -2}, checked)
+}
+
+func TestKeywordTypesOfConjunctions(t *testing.T) {
+	suite := NewSuite(WithFeaturesPath("features/keyword_types.feature"))
+	suite.AddStep(`the step passes`, pass)
+
+	require.NoError(t, suite.Run())
+
+	types := []msgs.StepKeywordType{}
+	for _, step := range suite.results[0].Scenarios[0].Steps {
+		types = append(types, step.KeywordType)
+	}
+
+	require.Equal(t, []msgs.StepKeywordType{
+		msgs.StepKeywordType_CONTEXT,
+		msgs.StepKeywordType_CONTEXT,
+		msgs.StepKeywordType_ACTION,
+		msgs.StepKeywordType_OUTCOME,
+		msgs.StepKeywordType_OUTCOME,
+	}, types)
+}
+
+func TestLocalizedKeywordTypes(t *testing.T) {
+	suite := NewSuite(WithFeaturesPath("features/french.feature"))
+	suite.AddStep(`I add {int} and {int}`, add)
+	suite.AddStep(`the result should equal {int}`, check)
+
+	require.NoError(t, suite.Run())
+
+	types := []msgs.StepKeywordType{}
+	for _, step := range suite.results[0].Scenarios[0].Steps {
+		types = append(types, step.KeywordType)
+	}
+
+	require.Equal(t, []msgs.StepKeywordType{
+		msgs.StepKeywordType_CONTEXT,
+		msgs.StepKeywordType_ACTION,
+		msgs.StepKeywordType_ACTION,
+		msgs.StepKeywordType_OUTCOME,
+		msgs.StepKeywordType_OUTCOME,
+	}, types)
+}
+
+func TestParameterTypeTransform(t *testing.T) {
+	var date time.Time
+	suite := NewSuite(WithFeaturesPath("features/transforms.feature"))
+	suite.AddParameterTypeTransform(`{date}`, `\d{4}-\d{2}-\d{2}`, func(value string) (interface{}, error) {
+		return time.Parse("2006-01-02", value)
+	})
+	suite.AddStep(`the release date is {date}`, func(_ context.Context, d time.Time) {
+		date = d
+	})
+
+	suite.RunWithT(t)
+
+	require.Equal(t, time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC), date)
+}
+
+func TestParameterTypeTransformFailure(t *testing.T) {
+	suite := NewSuite()
+	suite.AddParameterTypeTransform(`{date}`, `\d{4}-\d{2}-\d{2}`, func(value string) (interface{}, error) {
+		return nil, fmt.Errorf("%s is not a valid date", value)
+	})
+	suite.AddStep(`the release date is {date}`, func(_ context.Context, _ time.Time) {})
+
+	tester := &mockTester{}
+	suite.runScenario(tester, &models.Feature{}, &msgs.Scenario{
+		Steps: []*msgs.Step{
+			{Keyword: "When ", Text: "the release date is 2006-13-45", Location: &msgs.Location{Line: 3}},
+		},
+	}, nil)
+
+	require.Len(t, tester.errors, 1)
+	require.Contains(t, tester.errors[0], "2006-13-45 is not a valid date")
+}
+
+func TestNamedGroups(t *testing.T) {
+	type apples struct {
+		User  string `gobdd:"user"`
+		Count int    `gobdd:"count"`
+	}
+
+	var gotApples apples
+	var gotPears map[string]string
+
+	suite := NewSuite(WithFeaturesPath("features/named_groups.feature"))
+	suite.AddStep(`(?P<user>\w+) has (?P<count>\d+) apples`, func(_ context.Context, a apples) {
+		gotApples = a
+	})
+	suite.AddStep(`(?P<user>\w+) has (?P<count>\d+) pears`, func(_ context.Context, p map[string]string) {
+		gotPears = p
+	})
+
+	suite.RunWithT(t)
+
+	require.Equal(t, apples{User: "John", Count: 3}, gotApples)
+	require.Equal(t, map[string]string{"user": "Jane", "count": "5"}, gotPears)
+}
+
+func TestWithStepTimeout(t *testing.T) {
+	type valueKey struct{}
+
+	suite := NewSuite(WithStepTimeout(50 * time.Millisecond))
+	suite.AddStep(`the step sets a value`, func(ctx context.Context) context.Context {
+		return context.WithValue(ctx, valueKey{}, "value")
+	})
+	suite.AddStep(`the step checks the value`, func(t StepTest, ctx context.Context) {
+		if ctx.Value(valueKey{}) != "value" || ctx.Err() != nil {
+			t.Error("the context of the previous step is not available")
+		}
+	})
+	suite.AddStep(`the step hangs`, func(_ context.Context) {
+		time.Sleep(time.Second)
+	})
+
+	tester := &mockTester{}
+	start := time.Now()
+	suite.runScenario(tester, &models.Feature{}, &msgs.Scenario{
+		Steps: []*msgs.Step{
+			{Keyword: "Given ", Text: "the step sets a value", Location: &msgs.Location{Line: 3}},
+			{Keyword: "When ", Text: "the step checks the value", Location: &msgs.Location{Line: 4}},
+			{Keyword: "Then ", Text: "the step hangs", Location: &msgs.Location{Line: 5}},
+		},
+	}, nil)
+
+	require.Less(t, int64(time.Since(start)), int64(time.Second))
+	require.Equal(t, []string{"Then the step hangs (line 5): the step timed out after 50ms"}, tester.errors)
+}
+
+func TestWithScenarioRetry(t *testing.T) {
+	attempts := 0
+	report := &bytes.Buffer{}
+	suite := NewSuite(
+		WithScenarioRetry(2),
+		WithJSONReport(report),
+		WithBeforeScenario(func(ctx context.Context) {
+			attempts++
+		}),
+		WithInlineFeature("flaky.feature", `Feature: flaky scenarios
+  Scenario: flaky
+    When the step passes
+    Then the step fails on the first attempt
+`),
+	)
+	suite.AddStep(`the step passes`, pass)
+	suite.AddStep(`the step fails on the first attempt`, func(t StepTest, ctx context.Context) {
+		if attempts == 1 {
+			t.Error("the first attempt failed")
+		}
+	})
+
+	suite.RunWithT(t)
+
+	require.Equal(t, 2, attempts)
+
+	scenario := suite.results[0].Scenarios[0]
+	require.Equal(t, models.Passed, scenario.Result())
+	require.Equal(t, 2, scenario.Attempts)
+	require.Len(t, scenario.Steps, 2)
+
+	var features []struct {
+		Scenarios []struct {
+			Attempts int
+		}
+	}
+	require.NoError(t, json.Unmarshal(report.Bytes(), &features))
+	require.Equal(t, 2, features[0].Scenarios[0].Attempts)
+}
+
+func TestWithScenarioRetryReportsLastAttempt(t *testing.T) {
+	suite := NewSuite(WithScenarioRetry(2), WithOutput(io.Discard),
+		WithInlineFeature("failing.feature", `Feature: failing scenarios
+  Scenario: failing
+    When the step fails
+`))
+	suite.AddStep(`the step fails`, failure)
+
+	scenarios, err := suite.RunCollect()
+
+	require.NoError(t, err)
+	require.Equal(t, models.Failed, scenarios[0].Result)
+	require.Equal(t, 3, scenarios[0].Attempts)
+	require.Len(t, scenarios[0].Steps, 1)
+}
+
+func TestWithSuiteTimeout(t *testing.T) {
+	suite := NewSuite(WithSuiteTimeout(20*time.Millisecond), WithOutput(io.Discard),
+		WithInlineFeature("slow.feature", `Feature: slow scenarios
+  Scenario: first
+    When the step is slow
+
+  Scenario: second
+    When the step is slow
+
+  Scenario: third
+    When the step is slow
+`))
+	suite.AddStep(`the step is slow`, func(ctx context.Context) {
+		time.Sleep(50 * time.Millisecond)
+	})
+
+	scenarios, err := suite.RunCollect()
+
+	require.EqualError(t, err, "the suite timed out after 20ms")
+	require.Len(t, scenarios, 3)
+	require.Equal(t, models.Passed, scenarios[0].Result)
+	require.Equal(t, models.Skipped, scenarios[1].Result)
+	require.Equal(t, models.Skipped, scenarios[2].Result)
+}
+
+func TestWithScenarioTimeout(t *testing.T) {
+	afterScenario := false
+	suite := NewSuite(WithScenarioTimeout(150*time.Millisecond), WithAfterScenario(func(ctx context.Context) {
+		afterScenario = true
+	}))
+	suite.AddStep(`the step is slow`, func(_ context.Context) {
+		time.Sleep(60 * time.Millisecond)
+	})
+
+	steps := []*msgs.Step{}
+	for line := int64(3); line <= 6; line++ {
+		steps = append(steps, &msgs.Step{Keyword: "When ", Text: "the step is slow", Location: &msgs.Location{Line: line}})
+	}
+
+	tester := &mockTester{}
+	feature := &models.Feature{}
+	suite.runScenario(tester, feature, &msgs.Scenario{Steps: steps}, nil)
+
+	require.Equal(t, []string{"When the step is slow (line 5): the scenario timed out after 150ms"}, tester.errors)
+	require.True(t, afterScenario)
+
+	results := []models.Result{}
+	for _, step := range feature.Scenarios[0].Steps {
+		results = append(results, step.Execution.Result)
+	}
+
+	require.Equal(t, []models.Result{models.Passed, models.Passed, models.Failed, models.Skipped}, results)
+}
+
+func TestCancelledContextIsNotReportedAsTimeout(t *testing.T) {
+	testCases := map[string]struct {
+		options  []func(*SuiteOptions)
+		expected string
+	}{
+		"without timeouts": {
+			expected: "Then the step passes (line 4): the scenario was cancelled: context canceled",
+		},
+		"with step timeout": {
+			options:  []func(*SuiteOptions){WithStepTimeout(time.Second)},
+			expected: "When the step waits for cancellation (line 3): the scenario was cancelled: context canceled",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			suite := NewSuite(append(testCase.options, WithContext(ctx))...)
+			suite.AddStep(`the step waits for cancellation`, func(ctx context.Context) {
+				<-ctx.Done()
+			})
+			suite.AddStep(`the step passes`, pass)
+
+			time.AfterFunc(20*time.Millisecond, cancel)
+
+			tester := &mockTester{}
+			suite.runScenario(tester, &models.Feature{}, &msgs.Scenario{Steps: []*msgs.Step{
+				{Keyword: "When ", Text: "the step waits for cancellation", Location: &msgs.Location{Line: 3}},
+				{Keyword: "Then ", Text: "the step passes", Location: &msgs.Location{Line: 4}},
+			}}, nil)
+
+			require.Equal(t, []string{testCase.expected}, tester.errors)
+		})
+	}
+}
+
+func TestWithStepRetry(t *testing.T) {
+	newSuite := func(options ...func(*SuiteOptions)) *Suite {
+		calls := 0
+		suite := NewSuite(append(options, WithFeaturesPath("features/retry.feature"))...)
+		suite.AddStep(`the step fails twice`, func(t StepTest, _ context.Context) {
+			calls++
+			if calls <= 2 {
+				t.Errorf("attempt %d failed", calls)
+			}
+		})
+		suite.AddStep(`the step passes`, pass)
+
+		return suite
+	}
+
+	withRetry := newSuite(WithStepRetry(2, time.Millisecond))
+	require.NoError(t, withRetry.Run())
+	require.Equal(t, models.Passed, withRetry.results[0].Scenarios[0].Result())
+
+	withoutRetry := newSuite()
+	require.NoError(t, withoutRetry.Run())
+	require.Equal(t, models.Failed, withoutRetry.results[0].Scenarios[0].Result())
+}
+
+func TestWithLineFilter(t *testing.T) {
+	testCases := map[string]struct {
+		line     int
+		expected []string
+	}{
+		"scenario keyword":     {line: 5, expected: []string{"second"}},
+		"scenario step":        {line: 6, expected: []string{"second"}},
+		"scenario outline":     {line: 8, expected: []string{"third", "fourth"}},
+		"row of examples":      {line: 13, expected: []string{"fourth"}},
+		"line before scenario": {line: 1, expected: []string{}},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			executed := []string{}
+			suite := NewSuite(
+				WithFeaturesPath("features/*.feature"),
+				WithLineFilter("features/lines.feature", testCase.line),
+			)
+			suite.AddStep(`the "(\w+)" scenario runs`, func(_ context.Context, name string) {
+				executed = append(executed, name)
+			})
+
+			require.NoError(t, suite.Run())
+			require.Equal(t, testCase.expected, executed)
+		})
+	}
+}
+
+func TestPanickingStepIsReportedAsFailed(t *testing.T) {
+	suite := NewSuite()
+	suite.AddStep(`the step panics`, panics)
+
+	tester := &mockTester{}
+	feature := &models.Feature{}
+	suite.runScenario(tester, feature, &msgs.Scenario{
+		Steps: []*msgs.Step{
+			{Keyword: "When ", Text: "the step panics", Location: &msgs.Location{Line: 3}},
+		},
+	}, nil)
+
+	scenario := feature.Scenarios[0]
+	require.Equal(t, models.Failed, scenario.Result())
+	require.Equal(t, []string{"When the step panics (line 3): the step panicked"}, tester.errors)
+
+	execution := scenario.Steps[0].Execution
+	require.EqualError(t, execution.Err, "the step panicked")
+	require.Contains(t, string(execution.Stack), "gobdd.panics")
+}
+
+func TestWithFeaturesFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"embedded/sum.feature": {Data: []byte(`Feature: embedded feature
+  Scenario: adding numbers
+    When I add 1 and 2
+    Then the result should equal 3
+`)},
+		"embedded/notes.txt": {Data: []byte("not a feature")},
+	}
+
+	executed := 0
+	suite := NewSuite(WithFeaturesFS(fsys, "embedded/*.feature"))
+	suite.AddStep(`I add {int} and {int}`, add)
+	suite.AddStep(`the result should equal {int}`, func(t StepTest, ctx context.Context, sum int) {
+		executed++
+		check(t, ctx, sum)
+	})
+
+	suite.RunWithT(t)
+
+	require.Equal(t, 1, executed)
+}
+
+func TestWithFeaturesFSMultiplePatterns(t *testing.T) {
+	feature := func(name string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte("Feature: " + name + "\n  Scenario: " + name + "\n    When I add 1 and 2\n")}
+	}
+	fsys := fstest.MapFS{
+		"features/unit.feature":        feature("unit"),
+		"integration/db.feature":       feature("db"),
+		"integration/api.feature":      feature("api"),
+		"integration/api.feature.orig": feature("backup"),
+	}
+
+	scenarios := []string{}
+	suite := NewSuite(
+		WithFeaturesFS(fsys, "features/*.feature", "integration/*.feature", "integration/api.*"),
+		WithBeforeScenario(func(ctx context.Context) {
+			scenarios = append(scenarios, ScenarioName(ctx))
+		}),
+	)
+	suite.AddStep(`I add {int} and {int}`, add)
+
+	suite.RunWithT(t)
+
+	require.Equal(t, []string{"unit", "api", "db", "backup"}, scenarios)
+}
+
+func TestFeaturesOptionsUsedTwice(t *testing.T) {
+	fsys := fstest.MapFS{
+		"features/unit.feature":   {Data: []byte("Feature: unit\n")},
+		"integration/db.feature":  {Data: []byte("Feature: db\n")},
+		"integration/api.feature": {Data: []byte("Feature: api\n")},
+		"integration/ignored.txt": {Data: []byte("not a feature")},
+	}
+	otherFS := fstest.MapFS{"features/other.feature": {Data: []byte("Feature: other\n")}}
+
+	testCases := map[string]struct {
+		options  []func(*SuiteOptions)
+		expected []string
+	}{
+		"paths": {
+			options: []func(*SuiteOptions){
+				WithFeaturesPath("features/report.feature"),
+				WithFeaturesPath("features/pending.feature", "features/report.feature"),
+			},
+			expected: []string{"features/report.feature", "features/pending.feature"},
+		},
+		"filesystem": {
+			options: []func(*SuiteOptions){
+				WithFeaturesFS(fsys, "features/*.feature"),
+				WithFeaturesFS(fsys, "integration/*.feature", "features/unit.feature"),
+			},
+			expected: []string{"features/unit.feature", "integration/api.feature", "integration/db.feature"},
+		},
+		"another filesystem": {
+			options: []func(*SuiteOptions){
+				WithFeaturesFS(fsys, "features/*.feature"),
+				WithFeaturesFS(otherFS, "features/*.feature"),
+			},
+			expected: []string{"features/other.feature"},
+		},
+		"paths after filesystem": {
+			options: []func(*SuiteOptions){
+				WithFeaturesFS(fsys, "integration/*.feature"),
+				WithFeaturesPath("features/report.feature"),
+			},
+			expected: []string{"features/report.feature"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			suite := NewSuite(testCase.options...)
+
+			require.Equal(t, testCase.expected, suite.options.features)
+		})
+	}
+}
+
+func TestFeaturesPatternWarnings(t *testing.T) {
+	fsys := fstest.MapFS{"features/unit.feature": {Data: []byte("Feature: unit\n")}}
+
+	testCases := map[string]func(*SuiteOptions){
+		"paths":      WithFeaturesPath("features/[.feature", "features/missing.feature", "features/empty.feature"),
+		"filesystem": WithFeaturesFS(fsys, "features/[.feature", "features/missing.feature", "features/unit.feature"),
+	}
+
+	for name, option := range testCases {
+		t.Run(name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			suite := NewSuite(option, WithOutput(output))
+
+			require.NoError(t, suite.Run())
+			require.Contains(t, output.String(),
+				"gobdd: the features pattern features/[.feature is malformed: syntax error in pattern\n")
+			require.Contains(t, output.String(), "gobdd: the features pattern features/missing.feature doesn't match any file\n")
+			require.NotContains(t, output.String(), "features/empty.feature doesn't match")
+			require.NotContains(t, output.String(), "features/unit.feature doesn't match")
+		})
+	}
+}
+
+func TestWithExcludePaths(t *testing.T) {
+	feature := func(name string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte("Feature: " + name + "\n  Scenario: " + name + "\n    When I add 1 and 2\n")}
+	}
+	fsys := fstest.MapFS{
+		"features/sum.feature":        feature("sum"),
+		"features/ignore_wip.feature": feature("wip"),
+		"features/product.feature":    feature("product"),
+	}
+
+	scenarios := []string{}
+	suite := NewSuite(
+		WithFeaturesFS(fsys, "features/*.feature"),
+		WithExcludePaths("features/ignore_*.feature"),
+		WithBeforeScenario(func(ctx context.Context) {
+			scenarios = append(scenarios, ScenarioName(ctx))
+		}),
+	)
+	suite.AddStep(`I add {int} and {int}`, add)
+
+	suite.RunWithT(t)
+
+	require.Equal(t, []string{"product", "sum"}, scenarios)
+}
+
+func TestWithFeaturesRecursive(t *testing.T) {
+	var scenarios []string
+	suite := NewSuite(WithFeaturesRecursive("features/nested", "*.feature"), WithBeforeScenario(func(ctx context.Context) {
+		scenarios = append(scenarios, ScenarioName(ctx))
+	}))
+	suite.AddStep(`I add {int} and {int}`, add)
+	suite.AddStep(`the result should equal {int}`, check)
+
+	suite.RunWithT(t)
+
+	require.ElementsMatch(t, []string{"top", "middle", "deep"}, scenarios)
+}
+
+func TestNoFeaturesFound(t *testing.T) {
+	output := &bytes.Buffer{}
+	suite := NewSuite(WithFeaturesPath("features/*.missing"), WithOutput(output))
+	err := suite.Run()
+
+	require.NoError(t, err)
+	require.Contains(t, output.String(), "no feature files found")
+}
+
+func TestWithOutput(t *testing.T) {
+	output := &bytes.Buffer{}
+	suite := NewSuite(WithFeaturesPath("features/report.feature"), WithOutput(output))
+	suite.AddStep(`the step passes`, pass)
+	suite.AddStep(`the step fails`, failure)
+
+	require.NoError(t, suite.Run())
+
+	require.Equal(t, strings.Join([]string{
+		"Then the step fails (features/report.feature:7): the step failed",
+		"2 scenarios (1 passed, 1 failed)",
+		"5 steps (3 passed, 1 failed, 1 skipped)",
+		"",
+	}, "\n"), output.String())
+}
+
+func TestWithRequireFeatures(t *testing.T) {
+	suite := NewSuite(WithFeaturesPath("features/*.missing"), WithRequireFeatures())
+
+	err := suite.Run()
+
+	require.EqualError(t, err, "no feature files found, make sure the features path is correct")
+}
+
+func TestAddStepf(t *testing.T) {
+	fsys := fstest.MapFS{
+		"colors.feature": {Data: []byte(`Feature: colors
+  Scenario: painting
+    When I paint it red
+    And I paint it green
+    And I paint it blue
+`)},
+	}
+
+	painted := []string{}
+	suite := NewSuite(WithFeaturesFS(fsys, "colors.feature"))
+	for _, color := range []string{"red", "green", "blue"} {
+		color := color
+		suite.AddStepf(`I paint it %s`, func(ctx context.Context) {
+			painted = append(painted, color)
+		}, color)
+	}
+
+	suite.RunWithT(t)
+
+	require.Equal(t, []string{"red", "green", "blue"}, painted)
+}
+
+func TestAddStepForKeyword(t *testing.T) {
+	fsys := fstest.MapFS{
+		"count.feature": {Data: []byte(`Feature: counting
+  Scenario: counting
+    Given the count is 5
+    And the count is 5
+    When I count
+    Then the count is 5
+    But the count is 5
+`)},
+	}
+
+	calls := []string{}
+	suite := NewSuite(WithFeaturesFS(fsys, "count.feature"))
+	suite.AddStep(`the count is {int}`, func(ctx context.Context, count int) {
+		calls = append(calls, "any")
+	})
+	suite.AddStepForKeyword("Given", `the count is {int}`, func(ctx context.Context, count int) {
+		calls = append(calls, "given")
+	})
+	suite.AddStepForKeyword("Then", `the count is {int}`, func(ctx context.Context, count int) {
+		calls = append(calls, "then")
+	})
+	suite.AddStepForKeyword("When", `I count`, func(ctx context.Context) {
+		calls = append(calls, "when")
+	})
+
+	suite.RunWithT(t)
+
+	require.Equal(t, []string{"given", "given", "when", "then", "then"}, calls)
+}
+
+func TestFindStepDefPrecedence(t *testing.T) {
+	newSuite := func(steps ...string) *Suite {
+		suite := NewSuite()
+		for _, expr := range steps {
+			suite.AddStep(expr, func(ctx context.Context, value string) {})
+		}
+
+		return suite
+	}
+
+	testCases := map[string]*Suite{
+		"generic first":           newSuite(`I have (\d+) apples`, `I have (3) apples?`),
+		"specific first":          newSuite(`I have (3) apples?`, `I have (\d+) apples`),
+		"unrelated step first":    newSuite(`I eat a (pear)`, `I have (3) apples?`, `I have (\d+) apples`),
+		"equally long expression": newSuite(`I have (\d+) apples`, `I have (\d+) apple.`, `I eat a (pear)`),
+	}
+
+	for name, suite := range testCases {
+		t.Run(name, func(t *testing.T) {
+			def, err := suite.findStepDef("I have 3 apples", "")
+			require.NoError(t, err)
+			require.Equal(t, `I have (\d+) apples`, def.usage.expr)
+		})
+	}
+}
+
+func TestAddStepForKeywordUnscopedFallback(t *testing.T) {
+	fsys := fstest.MapFS{
+		"count.feature": {Data: []byte(`Feature: counting
+  Scenario: counting
+    When the count is 5
+`)},
+	}
+
+	calls := []string{}
+	suite := NewSuite(WithFeaturesFS(fsys, "count.feature"))
+	suite.AddStepForKeyword("Given", `the count is {int}`, func(ctx context.Context, count int) {
+		calls = append(calls, "given")
+	})
+	suite.AddStep(`the count is {int}`, func(ctx context.Context, count int) {
+		calls = append(calls, "any")
+	})
+
+	suite.RunWithT(t)
+
+	require.Equal(t, []string{"any"}, calls)
+}
+
+func TestAddStepForKeywordPanicsOnUnsupportedKeyword(t *testing.T) {
+	suite := NewSuite()
+
+	require.Panics(t, func() {
+		suite.AddStepForKeyword("And", `the count is {int}`, func(ctx context.Context, count int) {})
+	})
+}
+
+func TestScenarioContextCancelledOnFailure(t *testing.T) {
+	fsys := fstest.MapFS{
+		"worker.feature": {Data: []byte(`Feature: workers
+  Scenario: stopping the worker
+    Given the worker is started
+    When the worker is running
+    Then the step fails
+`)},
+	}
+
+	stopped := make(chan struct{})
+	suite := NewSuite(WithFeaturesFS(fsys, "worker.feature"))
+	suite.AddStep(`the worker is started`, func(ctx context.Context) {
+		go func() {
+			<-ctx.Done()
+			close(stopped)
+		}()
+	})
+	suite.AddStep(`the worker is running`, func(t StepTest, ctx context.Context) {
+		select {
+		case <-stopped:
+			t.Error("the worker should be running")
+		default:
+		}
+	})
+	suite.AddStep(`the step fails`, failure)
+
+	summary, err := suite.RunWithResult()
+	require.NoError(t, err)
+	require.Equal(t, ResultCounts{Passed: 2, Failed: 1}, summary.Steps)
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("the context of the scenario should be cancelled")
+	}
+}
+
+func TestCurrentStep(t *testing.T) {
+	var inStep, afterStep []string
+	var results []models.Result
+	suite := NewSuite(
+		WithFeaturesPath("features/report.feature"),
+		WithAfterStepCtx(func(ctx context.Context) context.Context {
+			step := CurrentStep(ctx)
+			afterStep = append(afterStep, fmt.Sprintf("%s%s:%d", step.Keyword, step.Text, step.Location.Line))
+			results = append(results, step.Execution.Result)
+			return ctx
+		}),
+	)
+	suite.AddStep(`the step passes`, func(ctx context.Context) {
+		inStep = append(inStep, CurrentStep(ctx).Text)
+	})
+	suite.AddStep(`the step fails`, failure)
+
+	require.Nil(t, CurrentStep(context.Background()))
+	require.NoError(t, suite.Run())
+
+	require.Equal(t, []string{"the step passes", "the step passes", "the step passes"}, inStep)
+	require.Equal(t, []string{
+		"When the step passes:3", "Then the step passes:4", "When the step passes:6", "Then the step fails:7",
+	}, afterStep)
+	require.Equal(t, []models.Result{models.Passed, models.Passed, models.Passed, models.Failed}, results)
+}
+
+func TestAfterHooksRunInReverseOrder(t *testing.T) {
+	calls := []string{}
+	hook := func(name string) func(ctx context.Context) {
+		return func(ctx context.Context) {
+			calls = append(calls, name)
+		}
+	}
+
+	options := []func(*SuiteOptions){WithInlineFeature("hooks.feature", `Feature: hooks
+  Scenario: hooks
+    When the step passes
+`)}
+	for _, n := range []string{"1", "2", "3"} {
+		options = append(options,
+			WithBeforeScenario(hook("before scenario "+n)),
+			WithAfterScenario(hook("after scenario "+n)),
+			WithBeforeStep(hook("before step "+n)),
+			WithAfterStep(hook("after step "+n)),
+		)
+	}
+
+	suite := NewSuite(options...)
+	suite.AddStep(`the step passes`, pass)
+	suite.RunWithT(t)
+
+	require.Equal(t, []string{
+		"before scenario 1", "before scenario 2", "before scenario 3",
+		"before step 1", "before step 2", "before step 3",
+		"after step 3", "after step 2", "after step 1",
+		"after scenario 3", "after scenario 2", "after scenario 1",
+	}, calls)
+}
+
+func TestAfterStepHookReceivesResult(t *testing.T) {
+	var results []models.Result
+	var errs []string
+	suite := NewSuite(
+		WithInlineFeature("results.feature", `Feature: step results
+  Scenario: passing then failing
+    When the step passes
+    Then the step fails
+    And the step passes
+`),
+		WithAfterStep(func(ctx context.Context) {
+			execution := CurrentStep(ctx).Execution
+			results = append(results, execution.Result)
+			errs = append(errs, errorMessage(execution.Err))
+		}),
+		WithOutput(io.Discard),
+	)
+	suite.AddStep(`the step passes`, pass)
+	suite.AddStep(`the step fails`, failure)
+
+	require.NoError(t, suite.Run())
+
+	require.Equal(t, []models.Result{models.Passed, models.Failed}, results)
+	require.Equal(t, []string{"", "the step failed"}, errs)
+}
+
+func TestStepHooksMatching(t *testing.T) {
+	var before, after []string
+	suite := NewSuite(
+		WithFeaturesPath("features/report.feature"),
+		WithBeforeStepMatching(`fails$`, func(ctx context.Context) {
+			before = append(before, CurrentStep(ctx).Text)
+		}),
+		WithAfterStepMatching(`^the step passes`, func(ctx context.Context) {
+			after = append(after, CurrentStep(ctx).Text)
+		}),
+	)
+	suite.AddStep(`the step passes`, pass)
+	suite.AddStep(`the step fails`, failure)
+
+	require.NoError(t, suite.Run())
+
+	require.Equal(t, []string{"the step fails"}, before)
+	require.Equal(t, []string{"the step passes", "the step passes", "the step passes"}, after)
+}
+
+func TestStepHooksMatchingPanicOnInvalidPattern(t *testing.T) {
+	require.Panics(t, func() {
+		NewSuite(WithBeforeStepMatching(`(`, func(ctx context.Context) {}))
+	})
+}
+
+func TestStepWithoutContext(t *testing.T) {
+	fsys := fstest.MapFS{
+		"no_context.feature": {Data: []byte(`Feature: steps without the context
+  Scenario: running steps without the context
+    Given the light is on
+    When I wait 3 seconds
+    Then John has 5 apples
+`)},
+	}
+
+	executed := []string{}
+	suite := NewSuite(WithFeaturesFS(fsys, "no_context.feature"))
+	suite.AddStep(`the light is on`, func() {
+		executed = append(executed, "light")
+	})
+	suite.AddStep(`I wait {int} seconds`, func(seconds int) {
+		executed = append(executed, fmt.Sprintf("wait %d", seconds))
+	})
+	suite.AddStep(`(\w+) has {int} apples`, func(t StepTest, name string, count int) {
+		executed = append(executed, fmt.Sprintf("%s %d", name, count))
+	})
+
+	suite.RunWithT(t)
+
+	require.Equal(t, []string{"light", "wait 3", "John 5"}, executed)
+}
+
+func TestOptionalGroups(t *testing.T) {
+	fsys := fstest.MapFS{
+		"apples.feature": {Data: []byte(`Feature: pluralization
+  Scenario: counting apples
+    Given I have 1 apple
+    And I have 3 apples
+    When I eat 1 pear
+    And I eat 2 pears
+`)},
+	}
+
+	apples, pears := []int{}, []int{}
+	suite := NewSuite(WithFeaturesFS(fsys, "apples.feature"))
+	suite.AddStep(`I have (\d+) apples?`, func(count int) {
+		apples = append(apples, count)
+	})
+	suite.AddStep(`I eat (\d+) pear(s)?`, func(count int) {
+		pears = append(pears, count)
+	})
+
+	suite.RunWithT(t)
+
+	require.Equal(t, []int{1, 3}, apples)
+	require.Equal(t, []int{1, 2}, pears)
+}
+
+func TestWithNormalizeWhitespace(t *testing.T) {
+	fsys := fstest.MapFS{
+		"spaces.feature": {Data: []byte("Feature: whitespace\n  Scenario: adding numbers\n" +
+			"    When I add  1   and 2 \t\n    Then the result should equal 3\n")},
+	}
+
+	for name, tc := range map[string]struct {
+		options []func(*SuiteOptions)
+		result  ResultCounts
+	}{
+		"without the option": {result: ResultCounts{Undefined: 1}},
+		"with the option":    {options: []func(*SuiteOptions){WithNormalizeWhitespace()}, result: ResultCounts{Passed: 1}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			options := append([]func(*SuiteOptions){
+				WithFeaturesFS(fsys, "spaces.feature"), WithUndefinedStepSnippets(io.Discard),
+			}, tc.options...)
+			suite := NewSuite(options...)
+			suite.AddStep(`^I add (\d+) and (\d+)$`, add)
+			suite.AddStep(`^the result should equal (\d+)$`, check)
+
+			summary, err := suite.RunWithResult()
+
+			require.NoError(t, err)
+			require.Equal(t, tc.result, summary.Scenarios)
+		})
+	}
+}
+
+func TestWithCaseInsensitiveSteps(t *testing.T) {
+	fsys := fstest.MapFS{
+		"case.feature": {Data: []byte(`Feature: case insensitive steps
+  Scenario: logging in
+    Given I Log In as John
+    When I ADD 1 and 2
+    Then The Result Should Equal 3
+`)},
+	}
+
+	user := ""
+	suite := NewSuite(WithFeaturesFS(fsys, "case.feature"), WithCaseInsensitiveSteps())
+	suite.AddStep(`i log in as {word}`, func(name string) {
+		user = name
+	})
+	suite.AddStep(`I add {int} and {int}`, add)
+	suite.AddRegexStep(regexp.MustCompile(`the result should equal (\d+)`), check)
+
+	suite.RunWithT(t)
+
+	require.Equal(t, "John", user)
+}
+
+func TestUnusedSteps(t *testing.T) {
+	suite := NewSuite(WithFeaturesPath("features/example.feature"))
+	suite.AddStep(`I add (\d+) and (\d+)`, add)
+	suite.AddStep(`the result should equal (\d+)`, check)
+	suite.AddStep(`I subtract (\d+) from (\d+)`, add)
+
+	suite.RunWithT(t)
+
+	require.Equal(t, []string{`I subtract (\d+) from (\d+)`}, suite.UnusedSteps())
+}
+
+func TestSteps(t *testing.T) {
+	suite := NewSuite()
+	suite.AddStep(`I add {int} and {int}`, add)
+	suite.AddStep(`the result should equal (\d+)`, check)
+	suite.AddStep(`I wait {duration}`, func(ctx context.Context, d time.Duration) {})
+
+	steps := suite.Steps()
+
+	require.Equal(t, []StepInfo{
+		{
+			Expression:     `I add {int} and {int}`,
+			ParameterTypes: []string{"{int}", "{int}"},
+			Arguments:      2,
+			Signature:      "func(gobdd.StepTest, context.Context, int, int) context.Context",
+		},
+		{
+			Expression:     `the result should equal (\d+)`,
+			ParameterTypes: []string{},
+			Arguments:      1,
+			Signature:      "func(gobdd.StepTest, context.Context, int)",
+		},
+		{
+			Expression:     `I wait {duration}`,
+			ParameterTypes: []string{"{duration}"},
+			Arguments:      1,
+			Signature:      "func(context.Context, time.Duration)",
+		},
+	}, steps)
+}
+
+func TestWithContext(t *testing.T) {
+	type clientKey struct{}
+
+	var inHook, inStep interface{}
+	ctx := context.WithValue(context.Background(), clientKey{}, "client")
+	suite := NewSuite(WithFeaturesPath("features/example.feature"), WithContext(ctx),
+		WithBeforeScenario(func(ctx context.Context) {
+			inHook = ctx.Value(clientKey{})
+		}))
+	suite.AddStep(`I add (\d+) and (\d+)`, add)
+	suite.AddStep(`the result should equal (\d+)`, func(t StepTest, ctx context.Context, sum int) {
+		inStep = ctx.Value(clientKey{})
+		check(t, ctx, sum)
+	})
+
+	suite.RunWithT(t)
+
+	require.Equal(t, "client", inHook)
+	require.Equal(t, "client", inStep)
+}
+
+func TestHooksReturningContext(t *testing.T) {
+	type scenarioKey struct{}
+	type stepKey struct{}
+
+	var steps, afterStep []interface{}
+	suite := NewSuite(
+		WithFeaturesPath("features/example.feature"),
+		WithBeforeScenarioCtx(func(ctx context.Context) context.Context {
+			return context.WithValue(ctx, scenarioKey{}, "scenario")
+		}),
+		WithBeforeStepCtx(func(ctx context.Context) context.Context {
+			return context.WithValue(ctx, stepKey{}, len(steps))
+		}),
+		WithAfterStepCtx(func(ctx context.Context) context.Context {
+			afterStep = append(afterStep, ctx.Value(stepKey{}))
+			return ctx
+		}),
+	)
+	suite.AddStep(`I add (\d+) and (\d+)`, func(t StepTest, ctx context.Context, var1, var2 int) context.Context {
+		steps = append(steps, ctx.Value(scenarioKey{}))
+		return add(t, ctx, var1, var2)
+	})
+	suite.AddStep(`the result should equal (\d+)`, func(t StepTest, ctx context.Context, sum int) {
+		steps = append(steps, ctx.Value(scenarioKey{}))
+		check(t, ctx, sum)
+	})
+
+	suite.RunWithT(t)
+
+	require.Equal(t, []interface{}{"scenario", "scenario"}, steps)
+	require.Equal(t, []interface{}{0, 1}, afterStep)
+}
+
+func TestScenarioResultInAfterScenarioHook(t *testing.T) {
+	results := map[string]models.Result{}
+	suite := NewSuite(WithFeaturesPath("features/report.feature"), WithAfterScenario(func(ctx context.Context) {
+		result, ok := ScenarioResult(ctx)
+		require.True(t, ok)
+		results[ScenarioName(ctx)] = result
+	}))
+	suite.AddStep(`the step passes`, pass)
+	suite.AddStep(`the step fails`, failure)
+
+	require.NoError(t, suite.Run())
+
+	require.Equal(t, map[string]models.Result{
+		"the passing scenario": models.Passed,
+		"the failing scenario": models.Failed,
+	}, results)
+}
+
+func TestContextReturnedByStep(t *testing.T) {
+	type nameKey struct{}
+
+	suite := NewSuite(WithFeaturesPath("features/context.feature"))
+	suite.AddStep(`my name is (\w+)`, func(_ StepTest, ctx context.Context, name string) context.Context {
+		return context.WithValue(ctx, nameKey{}, name)
+	})
+	suite.AddStep(`my name should be (\w+)`, func(t StepTest, ctx context.Context, name string) {
+		if received := ctx.Value(nameKey{}); received != name {
+			t.Errorf("expected name %s but %v received", name, received)
+		}
+	})
+
+	suite.RunWithT(t)
+}
+
+func TestSetValueAndGetValue(t *testing.T) {
+	type order struct {
+		Item     string
+		Quantity int
+	}
+
+	var count int
+	var placed order
+	var found, missing, wrongType bool
+	suite := NewSuite(WithInlineFeature("values.feature", `Feature: values
+  Scenario: passing values between steps
+    Given I order 3 pizzas
+    Then the order is stored
+`))
+	suite.AddStep(`I order {int} {word}`, func(ctx context.Context, quantity int, item string) context.Context {
+		ctx = SetValue(ctx, "count", quantity)
+
+		return SetValue(ctx, "order", order{Item: item, Quantity: quantity})
+	})
+	suite.AddStep(`the order is stored`, func(ctx context.Context) {
+		count, found = GetValue[int](ctx, "count")
+		placed, _ = GetValue[order](ctx, "order")
+		_, missing = GetValue[int](ctx, "missing")
+		_, wrongType = GetValue[string](ctx, "count")
+	})
+
+	suite.RunWithT(t)
+
+	require.True(t, found)
+	require.Equal(t, 3, count)
+	require.Equal(t, order{Item: "pizzas", Quantity: 3}, placed)
+	require.False(t, missing)
+	require.False(t, wrongType)
+}
+
+func TestDryRun(t *testing.T) {
+	executed := 0
+	hooks := 0
+	suite := NewSuite(WithFeaturesPath("features/dry_run/*.feature"), WithDryRun(),
+		WithBeforeScenario(func(ctx context.Context) {
+			hooks++
+		}))
+	suite.AddStep(`I have {int} cukes`, func(_ StepTest, _ context.Context, _ int) {
+		executed++
+	})
+	suite.AddStep(`I eat {int} cukes`, func(_ StepTest, _ context.Context, _ int) {
+		executed++
+	})
+
+	err := suite.Run()
+
+	require.EqualError(t, err, "the dry run found 1 invalid steps:\nWhen I cook 3 cukes (line 4): undefined step")
+	require.Equal(t, 0, executed)
+	require.Equal(t, 0, hooks)
+}
+
+func TestDryRunInvalidArguments(t *testing.T) {
+	suite := NewSuite(WithFeaturesPath("features/dry_run/valid.feature"), WithDryRun())
+	suite.AddParameterTypeTransform(`{count}`, `\d+`, func(value string) (interface{}, error) {
+		return strconv.Atoi(value)
+	})
+	suite.AddStep(`I have {int} cukes`, func(_ StepTest, _ context.Context, _ int, _ string) {})
+	suite.AddStep(`I eat {count} cukes`, func(_ StepTest, _ context.Context, _ string) {})
+
+	err := suite.Run()
+
+	require.EqualError(t, err, "the dry run found 2 invalid steps:\n"+
+		"Given I have 5 cukes (line 3): the step function func(gobdd.StepTest, context.Context, int, string) "+
+		"expects a doc string but the step has none\n"+
+		"When I eat 3 cukes (line 4): the argument 2 of the step function func(gobdd.StepTest, context.Context, string) "+
+		"has unsupported type string")
+}
+
+func TestStepsAfterFailureAreSkipped(t *testing.T) {
+	executed := []string{}
+	suite := NewSuite(WithFeaturesPath("features/fail_fast.feature"))
+	suite.AddStep(`the (\w+) scenario fails`, func(t StepTest, _ context.Context, name string) {
+		executed = append(executed, name)
+		t.Error("the step failed")
+	})
+	suite.AddStep(`the (\w+) scenario continues`, func(_ StepTest, _ context.Context, name string) {
+		executed = append(executed, name+" continued")
+	})
+
+	require.NoError(t, suite.Run())
+
+	require.Equal(t, []string{"first", "second"}, executed)
+}
+
+func TestWithFailFast(t *testing.T) {
+	executed := []string{}
+	suite := NewSuite(WithFeaturesPath("features/fail_fast.feature"), WithFailFast())
+	suite.AddStep(`the (\w+) scenario fails`, func(t StepTest, _ context.Context, name string) {
+		executed = append(executed, name)
+		t.Error("the step failed")
+	})
+	suite.AddStep(`the (\w+) scenario continues`, func(_ StepTest, _ context.Context, name string) {
+		executed = append(executed, name+" continued")
+	})
+
+	require.NoError(t, suite.Run())
+
+	require.Equal(t, []string{"first"}, executed)
+}
+
+func TestWithContinueOnFailure(t *testing.T) {
+	testCases := map[string]struct {
+		options  []func(*SuiteOptions)
+		expected []string
+	}{
+		"continue on failure set last": {
+			options:  []func(*SuiteOptions){WithFailFast(), WithContinueOnFailure()},
+			expected: []string{"first", "second"},
+		},
+		"fail fast set last": {
+			options:  []func(*SuiteOptions){WithContinueOnFailure(), WithFailFast()},
+			expected: []string{"first"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			executed := []string{}
+			options := append([]func(*SuiteOptions){WithFeaturesPath("features/fail_fast.feature")}, testCase.options...)
+			suite := NewSuite(options...)
+			suite.AddStep(`the (\w+) scenario fails`, func(t StepTest, _ context.Context, name string) {
+				executed = append(executed, name)
+				t.Error("the step failed")
+			})
+			suite.AddStep(`the (\w+) scenario continues`, func(_ StepTest, _ context.Context, name string) {
+				executed = append(executed, name+" continued")
+			})
+
+			require.NoError(t, suite.Run())
+
+			require.Equal(t, testCase.expected, executed)
+		})
+	}
+}
+
+func TestWithJSONReport(t *testing.T) {
+	report := &bytes.Buffer{}
+	suite := NewSuite(WithFeaturesPath("features/report.feature"), WithJSONReport(report))
+	suite.AddStep(`the step passes`, pass)
+	suite.AddStep(`the step fails`, failure)
+
+	require.NoError(t, suite.Run())
+
+	var features []struct {
+		Name      string
+		Scenarios []struct {
+			Name  string
+			Steps []struct {
+				Text     string
+				Result   string
+				Duration int64
+				Error    string
+			}
+		}
+	}
+	require.NoError(t, json.Unmarshal(report.Bytes(), &features))
+
+	require.Len(t, features, 1)
+	require.Equal(t, "reporting results", features[0].Name)
+	require.Len(t, features[0].Scenarios, 2)
+
+	passed := features[0].Scenarios[0]
+	require.Equal(t, "the passing scenario", passed.Name)
+	require.Len(t, passed.Steps, 2)
+	for _, step := range passed.Steps {
+		require.Equal(t, "passed", step.Result)
+		require.Empty(t, step.Error)
+	}
+
+	failed := features[0].Scenarios[1]
+	require.Equal(t, "the failing scenario", failed.Name)
+	require.Len(t, failed.Steps, 3)
+	require.Equal(t, "passed", failed.Steps[0].Result)
+	require.Equal(t, "failed", failed.Steps[1].Result)
+	require.Equal(t, "the step failed", failed.Steps[1].Error)
+	require.Equal(t, "skipped", failed.Steps[2].Result)
+	require.Zero(t, failed.Steps[2].Duration)
+}
+
+func TestDescriptionsInReports(t *testing.T) {
+	fsys := fstest.MapFS{
+		"described.feature": {Data: []byte(`Feature: described feature
+  Users can describe features
+  in a few lines.
+
+  Scenario: described scenario
+    The scenario explains itself.
+
+    When the step passes
+`)},
+	}
+
+	jsonReport := &bytes.Buffer{}
+	htmlReport := &bytes.Buffer{}
+	suite := NewSuite(WithFeaturesFS(fsys, "described.feature"), WithJSONReport(jsonReport), WithHTMLReport(htmlReport))
+	suite.AddStep(`the step passes`, pass)
+
+	scenarios, err := suite.RunCollect()
+	require.NoError(t, err)
+	require.Len(t, scenarios, 1)
+	require.Equal(t, "The scenario explains itself.", scenarios[0].Description)
+
+	var features []struct {
+		Description string
+		Scenarios   []struct {
+			Description string
+		}
+	}
+	require.NoError(t, json.Unmarshal(jsonReport.Bytes(), &features))
+	require.Equal(t, "Users can describe features\nin a few lines.", features[0].Description)
+	require.Equal(t, "The scenario explains itself.", features[0].Scenarios[0].Description)
+
+	require.Contains(t, htmlReport.String(), "<p class=\"description\">Users can describe features\nin a few lines.</p>")
+	require.Contains(t, htmlReport.String(), "<p class=\"description\">The scenario explains itself.</p>")
+}
+
+func TestStepArguments(t *testing.T) {
+	fsys := fstest.MapFS{
+		"args.feature": {Data: []byte(`Feature: step arguments
+  Scenario: a step with arguments
+    When John has 3 apples
+`)},
+	}
+	report := &bytes.Buffer{}
+	suite := NewSuite(WithFeaturesFS(fsys, "args.feature"), WithJSONReport(report))
+	suite.AddStep(`(\w+) has {int} apples`, func(ctx context.Context, name string, count int) {})
+
+	require.NoError(t, suite.Run())
+
+	require.Equal(t, []string{"John", "3"}, suite.results[0].Scenarios[0].Steps[0].ArgStrings())
+
+	var features []struct {
+		Scenarios []struct {
+			Steps []struct {
+				Args []string
+			}
+		}
+	}
+	require.NoError(t, json.Unmarshal(report.Bytes(), &features))
+	require.Equal(t, []string{"John", "3"}, features[0].Scenarios[0].Steps[0].Args)
+}
+
+func TestWithJUnitReport(t *testing.T) {
+	report := &bytes.Buffer{}
+	suite := NewSuite(WithFeaturesPath("features/report.feature"), WithJUnitReport(report))
+	suite.AddStep(`the step passes`, pass)
+	suite.AddStep(`the step fails`, failure)
+
+	require.NoError(t, suite.Run())
+
+	var suites struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Suites   []struct {
+			Name      string `xml:"name,attr"`
+			Tests     int    `xml:"tests,attr"`
+			Failures  int    `xml:"failures,attr"`
+			TestCases []struct {
+				Name    string `xml:"name,attr"`
+				Failure *struct {
+					Message string `xml:"message,attr"`
+					Text    string `xml:",chardata"`
+				} `xml:"failure"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	require.NoError(t, xml.Unmarshal(report.Bytes(), &suites))
+
+	require.Equal(t, 2, suites.Tests)
+	require.Equal(t, 1, suites.Failures)
+	require.Len(t, suites.Suites, 1)
+	require.Equal(t, "reporting results", suites.Suites[0].Name)
+	require.Equal(t, 1, suites.Suites[0].Failures)
+
+	cases := suites.Suites[0].TestCases
+	require.Len(t, cases, 2)
+	require.Equal(t, "the passing scenario", cases[0].Name)
+	require.Nil(t, cases[0].Failure)
+	require.Equal(t, "the failing scenario", cases[1].Name)
+	require.NotNil(t, cases[1].Failure)
+	require.Equal(t, "the step failed", cases[1].Failure.Message)
+	require.Equal(t, "Then the step fails (line 7): the step failed", cases[1].Failure.Text)
+}
+
+func TestJUnitReportOfPendingScenarios(t *testing.T) {
+	testCases := map[string]struct {
+		options  []func(*SuiteOptions)
+		failures int
+		skipped  int
+		failure  string
+	}{
+		"not strict": {skipped: 1},
+		"strict": {
+			options:  []func(*SuiteOptions){WithStrict()},
+			failures: 1,
+			failure:  "And the step is pending (line 4): pending step",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			report := &bytes.Buffer{}
+			suite := NewSuite(append(testCase.options,
+				WithFeaturesPath("features/pending.feature"), WithJUnitReport(report), WithOutput(io.Discard))...)
+			suite.AddStep(`the step passes`, pass)
+			suite.AddStep(`the step is pending`, func(ctx context.Context) {
+				Pending()
+			})
+
+			_, _ = suite.RunWithResult()
+
+			var suites struct {
+				Failures int `xml:"failures,attr"`
+				Skipped  int `xml:"skipped,attr"`
+				Suites   []struct {
+					TestCases []struct {
+						Failure *struct {
+							Text string `xml:",chardata"`
+						} `xml:"failure"`
+						Skipped *struct{} `xml:"skipped"`
+					} `xml:"testcase"`
+				} `xml:"testsuite"`
+			}
+			require.NoError(t, xml.Unmarshal(report.Bytes(), &suites))
+
+			require.Equal(t, testCase.failures, suites.Failures)
+			require.Equal(t, testCase.skipped, suites.Skipped)
+
+			junitCase := suites.Suites[0].TestCases[0]
+			if testCase.failure == "" {
+				require.Nil(t, junitCase.Failure)
+				require.NotNil(t, junitCase.Skipped)
+			} else {
+				require.NotNil(t, junitCase.Failure)
+				require.Equal(t, testCase.failure, junitCase.Failure.Text)
+				require.Nil(t, junitCase.Skipped)
+			}
+		})
+	}
+}
+
+func TestStepDuration(t *testing.T) {
+	suite := NewSuite(WithFeaturesPath("features/example.feature"))
+	suite.AddStep(`I add (\d+) and (\d+)`, func(t StepTest, ctx context.Context, var1, var2 int) context.Context {
+		time.Sleep(20 * time.Millisecond)
+		return add(t, ctx, var1, var2)
+	})
+	suite.AddStep(`the result should equal (\d+)`, check)
+
+	summary, err := suite.RunWithResult()
+	require.NoError(t, err)
+
+	steps := suite.results[0].Scenarios[0].Steps
+	require.GreaterOrEqual(t, int64(steps[0].Execution.Duration()), int64(20*time.Millisecond))
+	require.Less(t, int64(steps[1].Execution.Duration()), int64(20*time.Millisecond))
+	require.GreaterOrEqual(t, int64(summary.Duration), int64(20*time.Millisecond))
+	require.GreaterOrEqual(t, int64(summary.StepsDuration), int64(20*time.Millisecond))
+}
+
+func TestSummaryDurationIsWallTime(t *testing.T) {
+	suite := NewSuite(WithFeaturesPath("features/example.feature"), WithBeforeScenario(func(ctx context.Context) {
+		time.Sleep(30 * time.Millisecond)
+	}))
+	suite.AddStep(`I add (\d+) and (\d+)`, add)
+	suite.AddStep(`the result should equal (\d+)`, check)
+
+	summary, err := suite.RunWithResult()
+	require.NoError(t, err)
+
+	require.GreaterOrEqual(t, int64(summary.Duration), int64(30*time.Millisecond))
+	require.Less(t, int64(summary.StepsDuration), int64(30*time.Millisecond))
+}
+
+func TestWithHTMLReport(t *testing.T) {
+	report := &bytes.Buffer{}
+	suite := NewSuite(WithFeaturesPath("features/report.feature"), WithHTMLReport(report))
+	suite.AddStep(`the step passes`, pass)
+	suite.AddStep(`the step fails`, failure)
+
+	require.NoError(t, suite.Run())
+
+	html := report.String()
+	require.True(t, strings.HasPrefix(html, "<!DOCTYPE html>"))
+	require.Contains(t, html, "2 scenarios (1 passed, 1 failed)")
+	require.Contains(t, html, `<details class="passed">`)
+	require.Contains(t, html, `<summary>Scenario: the passing scenario`)
+	require.Contains(t, html, `<details class="failed" open>`)
+	require.Contains(t, html, `<summary>Scenario: the failing scenario`)
+	require.Contains(t, html, `<div class="error">line 7: the step failed</div>`)
+}
+
+func TestWithMessagesOutput(t *testing.T) {
+	output := &bytes.Buffer{}
+	suite := NewSuite(WithFeaturesPath("features/report.feature"), WithMessagesOutput(output))
+	suite.AddStep(`the step passes`, pass)
+	suite.AddStep(`the step fails`, failure)
+
+	require.NoError(t, suite.Run())
+
+	pickles := map[string]string{}
+	testCases := map[string]string{}
+	startedCases := map[string]string{}
+	statuses := map[string]msgs.TestStepResultStatus{}
+	results := map[string]msgs.TestStepResultStatus{}
+	var runFinished *msgs.TestRunFinished
+
+	dec := json.NewDecoder(output)
+	for dec.More() {
+		envelope := &msgs.Envelope{}
+		require.NoError(t, dec.Decode(envelope))
+
+		switch {
+		case envelope.Pickle != nil:
+			pickles[envelope.Pickle.Id] = envelope.Pickle.Name
+		case envelope.TestCase != nil:
+			testCases[envelope.TestCase.Id] = envelope.TestCase.PickleId
+		case envelope.TestCaseStarted != nil:
+			startedCases[envelope.TestCaseStarted.Id] = envelope.TestCaseStarted.TestCaseId
+			statuses[envelope.TestCaseStarted.Id] = msgs.TestStepResultStatus_PASSED
+		case envelope.TestStepFinished != nil:
+			if envelope.TestStepFinished.TestStepResult.Status == msgs.TestStepResultStatus_FAILED {
+				statuses[envelope.TestStepFinished.TestCaseStartedId] = msgs.TestStepResultStatus_FAILED
+			}
+		case envelope.TestCaseFinished != nil:
+			id := envelope.TestCaseFinished.TestCaseStartedId
+			results[pickles[testCases[startedCases[id]]]] = statuses[id]
+		case envelope.TestRunFinished != nil:
+			runFinished = envelope.TestRunFinished
+		}
+	}
+
+	require.Equal(t, map[string]msgs.TestStepResultStatus{
+		"the passing scenario": msgs.TestStepResultStatus_PASSED,
+		"the failing scenario": msgs.TestStepResultStatus_FAILED,
+	}, results)
+	require.NotNil(t, runFinished)
+	require.False(t, runFinished.Success)
+}
+
+func TestSkipTag(t *testing.T) {
+	testCases := map[string]struct {
+		options   []func(*SuiteOptions)
+		scenarios ResultCounts
+		wip       models.Result
+	}{
+		"@skip and @wip": {scenarios: ResultCounts{Passed: 1, Skipped: 2}, wip: models.Skipped},
+		"@skip and running @wip": {
+			options:   []func(*SuiteOptions){WithWIPStrict()},
+			scenarios: ResultCounts{Passed: 2, Skipped: 1},
+			wip:       models.Passed,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			hooks := 0
+			options := append([]func(*SuiteOptions){
+				WithFeaturesPath("features/skip.feature"),
+				WithBeforeScenario(func(ctx context.Context) { hooks++ }),
+			}, testCase.options...)
+			suite := NewSuite(options...)
+			suite.AddStep(`the step passes`, pass)
+			suite.AddStep(`the step fails`, failure)
+
+			summary, err := suite.RunWithResult()
+
+			require.NoError(t, err)
+			require.Equal(t, testCase.scenarios, summary.Scenarios)
+			require.Equal(t, testCase.scenarios.Passed, hooks)
+
+			skipped := suite.results[0].Scenarios[1]
+			require.Equal(t, "the skipped scenario", skipped.Name)
+			require.Equal(t, models.Skipped, skipped.Result())
+			require.Len(t, skipped.Steps, 2)
+
+			wip := suite.results[0].Scenarios[2]
+			require.Equal(t, "the work in progress scenario", wip.Name)
+			require.Equal(t, testCase.wip, wip.Result())
+		})
+	}
+}
+
+func TestRunWithResult(t *testing.T) {
+	suite := NewSuite(WithFeaturesPath("features/progress.feature"), WithUndefinedStepSnippets(io.Discard))
+	suite.AddStep(`the step passes`, pass)
+	suite.AddStep(`the step fails`, failure)
+
+	summary, err := suite.RunWithResult()
+
+	require.NoError(t, err)
+	require.Equal(t, 1, summary.Features)
+	require.Equal(t, ResultCounts{Passed: 1, Failed: 1, Undefined: 1}, summary.Scenarios)
+	require.Equal(t, ResultCounts{Passed: 2, Failed: 1, Skipped: 2, Undefined: 1}, summary.Steps)
+	require.Equal(t, 6, summary.Steps.Total())
+	require.False(t, summary.Succeeded())
+	require.Greater(t, int64(summary.Duration), int64(0))
+}
+
+func TestRunCollect(t *testing.T) {
+	fsys := fstest.MapFS{
+		"collect.feature": {Data: []byte(`Feature: collecting results
+  @smoke
+  Scenario: passing
+    When the step passes
+
+  Scenario: failing
+    When the step fails
+    Then the step passes
+`)},
+	}
+
+	suite := NewSuite(WithFeaturesFS(fsys, "collect.feature"), WithOutput(io.Discard))
+	suite.AddStep(`the step passes`, pass)
+	suite.AddStep(`the step fails`, failure)
+
+	results, err := suite.RunCollect()
+
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	require.Equal(t, "collecting results", results[0].Feature)
+	require.Equal(t, "passing", results[0].Name)
+	require.Equal(t, []string{"@smoke"}, results[0].Tags)
+	require.Equal(t, models.Passed, results[0].Result)
+	require.Equal(t, []CollectedStep{{Text: "the step passes", Result: models.Passed}}, results[0].Steps)
+
+	require.Equal(t, "failing", results[1].Name)
+	require.Empty(t, results[1].Tags)
+	require.Equal(t, models.Failed, results[1].Result)
+	require.Len(t, results[1].Steps, 2)
+	require.Equal(t, models.Failed, results[1].Steps[0].Result)
+	require.Error(t, results[1].Steps[0].Err)
+	require.Equal(t, CollectedStep{Text: "the step passes", Result: models.Skipped}, results[1].Steps[1])
+}
+
+func TestStrict(t *testing.T) {
+	fsys := fstest.MapFS{
+		"undefined.feature": {Data: []byte("Feature: strict\n  Scenario: undefined\n" +
+			"    When the step is undefined\n    Then the step passes\n")},
+		"pending.feature": {Data: []byte("Feature: strict\n  Scenario: pending\n" +
+			"    When the step is pending\n    Then the step passes\n")},
+	}
+
+	newSuite := func(feature string, options ...func(*SuiteOptions)) *Suite {
+		suite := NewSuite(append([]func(*SuiteOptions){WithFeaturesFS(fsys, feature)}, options...)...)
+		suite.AddStep(`the step passes`, pass)
+		suite.AddStep(`the step is pending`, func(ctx context.Context) {
+			Pending()
+		})
+
+		return suite
+	}
+
+	t.Run("undefined step in strict mode", func(t *testing.T) {
+		var summary Summary
+		var err error
+		require.NotPanics(t, func() {
+			summary, err = newSuite("undefined.feature", WithStrict()).RunWithResult()
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, ResultCounts{Undefined: 1}, summary.Scenarios)
+		require.Equal(t, ResultCounts{Skipped: 1, Undefined: 1}, summary.Steps)
+		require.False(t, summary.Succeeded())
+	})
+
+	t.Run("undefined step in non-strict mode", func(t *testing.T) {
+		var summary Summary
+		var err error
+		require.NotPanics(t, func() {
+			summary, err = newSuite("undefined.feature").RunWithResult()
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, ResultCounts{Undefined: 1}, summary.Scenarios)
+		require.False(t, summary.Succeeded())
+	})
+
+	t.Run("undefined step in parallel", func(t *testing.T) {
+		var summary Summary
+		var err error
+		require.NotPanics(t, func() {
+			summary, err = newSuite("undefined.feature", RunInParallel()).RunWithResult()
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, ResultCounts{Undefined: 1}, summary.Scenarios)
+	})
+
+	t.Run("pending step in strict mode", func(t *testing.T) {
+		summary, err := newSuite("pending.feature", WithStrict()).RunWithResult()
+
+		require.NoError(t, err)
+		require.Equal(t, ResultCounts{Pending: 1}, summary.Scenarios)
+		require.Equal(t, ResultCounts{Skipped: 1, Pending: 1}, summary.Steps)
+		require.False(t, summary.Succeeded())
+	})
+
+	t.Run("pending step in non-strict mode", func(t *testing.T) {
+		summary, err := newSuite("pending.feature").RunWithResult()
+
+		require.NoError(t, err)
+		require.Equal(t, ResultCounts{Pending: 1}, summary.Scenarios)
+		require.True(t, summary.Succeeded())
+	})
+}
+
+func TestPendingStep(t *testing.T) {
+	output := &bytes.Buffer{}
+	suite := NewSuite(WithFeaturesPath("features/pending.feature"), WithFormatter(NewProgressFormatter(output)))
+	suite.AddStep(`the step passes`, pass)
+	suite.AddStep(`the step is pending`, func(t StepTest, ctx context.Context) {
+		Pending()
+		t.Error("the step should stop after Pending")
+	})
+
+	summary, err := suite.RunWithResult()
+
+	require.NoError(t, err)
+	require.True(t, summary.Succeeded())
+
+	scenario := suite.results[0].Scenarios[0]
+	require.Equal(t, models.Pending, scenario.Result())
+	require.Equal(t, models.Passed, scenario.Steps[0].Execution.Result)
+	require.Equal(t, models.Pending, scenario.Steps[1].Execution.Result)
+	require.Equal(t, models.Skipped, scenario.Steps[2].Execution.Result)
+
+	lines := strings.Split(output.String(), "\n")
+	require.Equal(t, ".P-", lines[0])
+	require.Equal(t, "1 scenarios (1 pending)", lines[2])
+	require.Equal(t, "3 steps (1 passed, 1 pending, 1 skipped)", lines[3])
+}
+
+func TestStepOutput(t *testing.T) {
+	out := &bytes.Buffer{}
+	errOut := &bytes.Buffer{}
+	suite := NewSuite(WithFeaturesPath("features/pending.feature"), WithStepOutput(out, errOut))
+	suite.AddStep(`the step passes`, func(ctx context.Context) {
+		fmt.Fprintln(Out(ctx), "passed")
+	})
+	suite.AddStep(`the step is pending`, func(ctx context.Context) {
+		fmt.Fprintln(ErrOut(ctx), "pending")
+		Pending()
+	})
+
+	suite.RunWithT(t)
+
+	require.Equal(t, "passed\n", out.String())
+	require.Equal(t, "pending\n", errOut.String())
+}
+
+func TestRunModels(t *testing.T) {
+	scheme := &models.Scheme{}
+	require.NoError(t, scheme.Register(models.StepDefinition{
+		Expression: regexp.MustCompile(`the step passes`),
+		Function: func(ctx context.Context) error {
+			return nil
+		},
+	}))
+	require.NoError(t, scheme.Register(models.StepDefinition{
+		Expression: regexp.MustCompile(`the step fails`),
+		Function: func(ctx context.Context) error {
+			return errors.New("the step failed")
+		},
+	}))
+
+	suite := NewSuite(WithFeaturesPath("features/report.feature"))
+
+	features, err := suite.RunModels(scheme)
+
+	require.NoError(t, err)
+	require.Len(t, features, 1)
+	require.Equal(t, "features/report.feature", features[0].Uri)
+	require.Equal(t, "reporting results", features[0].Name)
+
+	scenarios := features[0].Scenarios
+	require.Len(t, scenarios, 2)
+	require.Equal(t, models.Passed, scenarios[0].Result())
+
+	failed := scenarios[1]
+	require.Equal(t, models.Failed, failed.Result())
+	require.Equal(t, models.Failed, failed.Steps[1].Execution.Result)
+	require.EqualError(t, failed.Steps[1].Execution.Err, "the step failed")
+	require.Equal(t, models.Skipped, failed.Steps[2].Execution.Result)
+}
+
+func TestRunModelsUndefinedStep(t *testing.T) {
+	suite := NewSuite(WithFeaturesPath("features/report.feature"))
+
+	_, err := suite.RunModels(&models.Scheme{})
+
+	require.ErrorIs(t, err, models.ErrNoStepDefFound)
+}
+
+func TestWithSlowestReport(t *testing.T) {
+	output := &bytes.Buffer{}
+	suite := NewSuite(WithFeaturesPath("features/slowest.feature"), WithOutput(output), WithSlowestReport(2))
+	suite.AddStep(`I wait {duration}`, func(ctx context.Context, d time.Duration) {
+		time.Sleep(d)
+	})
+
+	summary, err := suite.RunWithResult()
+	require.NoError(t, err)
+
+	require.Len(t, summary.FeatureDurations, 1)
+	require.GreaterOrEqual(t, int64(summary.FeatureDurations[0].Duration), int64(130*time.Millisecond))
+
+	require.Len(t, summary.ScenarioDurations, 3)
+	require.Equal(t, "the fast scenario", summary.ScenarioDurations[0].Name)
+	require.GreaterOrEqual(t, int64(summary.ScenarioDurations[1].Duration), int64(80*time.Millisecond))
+
+	slowest := summary.Slowest(2)
+	require.Len(t, slowest, 2)
+	require.Equal(t, "the slowest scenario", slowest[0].Name)
+	require.Equal(t, "the slow scenario", slowest[1].Name)
+
+	report := output.String()
+	require.Contains(t, report, "\nslowest scenarios:\n")
+	require.Regexp(t,
+		`features/slowest.feature:4 the slowest scenario\n.* features/slowest.feature:7 the slow scenario\n$`, report)
+	require.NotContains(t, report, "the fast scenario")
+}
+
+func TestSummarySlowest(t *testing.T) {
+	summary := Summary{ScenarioDurations: []Timing{
+		{Name: "fast", Duration: time.Millisecond},
+		{Name: "slowest", Duration: time.Second},
+		{Name: "slow", Duration: 100 * time.Millisecond},
+	}}
+
+	testCases := map[string]struct {
+		n        int
+		expected []string
+	}{
+		"negative":       {n: -1, expected: []string{}},
+		"zero":           {n: 0, expected: []string{}},
+		"fewer than all": {n: 2, expected: []string{"slowest", "slow"}},
+		"more than all":  {n: 5, expected: []string{"slowest", "slow", "fast"}},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			names := []string{}
+			for _, timing := range summary.Slowest(testCase.n) {
+				names = append(names, timing.Name)
+			}
+
+			require.Equal(t, testCase.expected, names)
+		})
+	}
+}
+
+func TestOutlineStepArguments(t *testing.T) {
+	messages := []string{}
+	users := [][]string{}
+	suite := NewSuite(WithFeaturesPath("features/outline_arguments.feature"))
+	suite.AddStep(`the message:`, func(ctx context.Context, doc *DocString) {
+		messages = append(messages, doc.Content)
+	})
+	suite.AddStep(`the user:`, func(ctx context.Context, table *Table) {
+		users = append(users, table.Rows()[0])
+	})
+
+	suite.RunWithT(t)
+
+	require.Equal(t, []string{"Hello John!", "Hello Jane!"}, messages)
+	require.Equal(t, [][]string{{"John", "42"}, {"Jane", "37"}}, users)
+}
+
+func TestDataTable(t *testing.T) {
+	var users []map[string]string
+	suite := NewSuite(WithFeaturesPath("features/datatable.feature"))
+	suite.AddStep(`the following users exist:`, func(ctx context.Context, table *Table) {
+		users = table.Maps()
+	})
+
+	suite.RunWithT(t)
+
+	require.Equal(t, []map[string]string{
+		{"name": "John", "age": "42"},
+		{"name": "Jane", "age": "37"},
+	}, users)
+}
+
+func TestDocString(t *testing.T) {
+	var user struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	var mediaType, message string
+
+	suite := NewSuite(WithFeaturesPath("features/docstring.feature"))
+	suite.AddStep(`the following user:`, func(t StepTest, ctx context.Context, doc *DocString) {
+		mediaType = doc.MediaType
+		if err := json.Unmarshal([]byte(doc.Content), &user); err != nil {
+			t.Fatal(err)
+		}
+	})
+	suite.AddStep(`the following message:`, func(ctx context.Context, content string) {
+		message = content
+	})
+
+	suite.RunWithT(t)
+
+	require.Equal(t, "json", mediaType)
+	require.Equal(t, "John", user.Name)
+	require.Equal(t, 42, user.Age)
+	require.Equal(t, "hello world", message)
+}
+
+func TestDocStringArgumentWithoutDocString(t *testing.T) {
+	suite := NewSuite(WithOutput(io.Discard), WithInlineFeature("docstring.feature", `Feature: doc strings
+  Scenario: missing doc string
+    When I add 2 and the following note:
+`))
+	suite.AddStep(`I add {int} and the following note:`, func(ctx context.Context, a int, note string) {})
+
+	scenarios, err := suite.RunCollect()
+
+	require.NoError(t, err)
+	require.Equal(t, models.Failed, scenarios[0].Steps[0].Result)
+	require.ErrorContains(t, scenarios[0].Steps[0].Err, "expects a doc string but the step has none")
+}
+
+func TestRunInParallel(t *testing.T) {
+	var running, overlapped int32
+
+	suite := NewSuite(WithFeaturesPath("features/parallel.feature"), RunInParallel())
+	suite.AddStep(`I wait for the other scenario`, func(ctx context.Context) {
+		atomic.AddInt32(&running, 1)
+
+		deadline := time.Now().Add(time.Second)
+		for time.Now().Before(deadline) {
+			if atomic.LoadInt32(&running) == 2 {
+				atomic.StoreInt32(&overlapped, 1)
+
+				return
+			}
+
+			time.Sleep(time.Millisecond)
+		}
+	})
+
+	suite.Run()
+
+	require.Equal(t, int32(1), atomic.LoadInt32(&overlapped), "the scenarios should run at the same time")
+}
+
+func TestWithMaxParallel(t *testing.T) {
+	var running, maxRunning int32
+
+	suite := NewSuite(WithFeaturesPath("features/max_parallel.feature"), RunInParallel(), WithMaxParallel(2))
+	suite.AddStep(`I take some time`, func(ctx context.Context) {
+		current := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+				break
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+	})
+
+	suite.Run()
+
+	require.Equal(t, int32(2), atomic.LoadInt32(&maxRunning))
+}
+
+type sumKey struct{}
+
+func addf(_ StepTest, ctx context.Context, var1, var2 float32) context.Context {
+	return context.WithValue(ctx, sumKey{}, var1+var2)
+}
+
+func add(_ StepTest, ctx context.Context, var1, var2 int) context.Context {
+	return context.WithValue(ctx, sumKey{}, var1+var2)
+}
+
+func checkf(t StepTest, ctx context.Context, sum float32) {
+	received, ok := ctx.Value(sumKey{}).(float32)
+	if !ok {
+		t.Error("the sum is not set")
+
+		return
+	}
+
+	if sum != received {
+		t.Error("the sum doesn't match")
+	}
+}
+
+func check(t StepTest, ctx context.Context, sum int) {
+	received, ok := ctx.Value(sumKey{}).(int)
+	if !ok {
+		t.Error("the sum is not set")
+		return
+	}
+
+	if sum != received {
+		t.Errorf("expected %d but %d received", sum, received)
+	}
+}
+
+func fail(t StepTest, _ context.Context) {
+	t.Error("the step should never be executed")
+}
+
+func failure(t StepTest, _ context.Context) {
+	t.Error("the step failed")
+}
+
+func panics(_ StepTest, _ context.Context) {
 	panic(errors.New("the step panicked"))
 }
 
-func pass(_ StepTest, _ Context) {}
+func pass(_ StepTest, _ context.Context) {}
 
 type mockTester struct {
 	fatalCalled int
@@ -339,15 +2980,8 @@ func (m *mockTester) Errorf(format string, a ...interface{}) {
 	m.errors = append(m.errors, fmt.Sprintf(format, a...))
 }
 
-func (m *mockTester) Parallel() {
-}
-
 func (m *mockTester) Fail() {
 }
 
 func (m *mockTester) FailNow() {
 }
-
-func (m *mockTester) Run(_ string, _ func(t *testing.T)) bool {
-	return true
-}
@@ -0,0 +1,132 @@
+package gobdd
+
+import (
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/go-bdd/gobdd/models"
+)
+
+type htmlReport struct {
+	Scenarios string
+	Steps     string
+	Features  []htmlFeature
+}
+
+type htmlFeature struct {
+	Keyword     string
+	Name        string
+	Description string
+	Scenarios   []htmlScenario
+}
+
+type htmlScenario struct {
+	Keyword     string
+	Name        string
+	Description string
+	Result      string
+	Duration    time.Duration
+	Steps       []htmlStep
+}
+
+type htmlStep struct {
+	Keyword  string
+	Text     string
+	Line     int64
+	Result   string
+	Duration time.Duration
+	Error    string
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>GoBDD report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+h2 { margin-top: 1.5em; }
+details { margin: 0.5em 0; border-left: 4px solid #ccc; padding-left: 0.5em; }
+summary { cursor: pointer; font-weight: bold; }
+ul { list-style: none; padding-left: 1em; }
+.duration { color: #888; font-size: 0.9em; }
+.description { color: #555; white-space: pre-line; }
+.error { color: #b00; white-space: pre-wrap; margin-left: 1em; }
+details.passed { border-color: #2a2; }
+details.failed { border-color: #d22; }
+details.skipped, details.undefined, details.pending { border-color: #db2; }
+li.passed { color: #272; }
+li.failed { color: #b00; }
+li.skipped, li.undefined, li.pending { color: #a80; }
+</style>
+</head>
+<body>
+<h1>GoBDD report</h1>
+<p>{{.Scenarios}}<br>{{.Steps}}</p>
+{{range .Features}}
+<h2>{{.Keyword}}: {{.Name}}</h2>
+{{if .Description}}<p class="description">{{.Description}}</p>
+{{end}}{{range .Scenarios}}
+<details class="{{.Result}}"{{if ne .Result "passed"}} open{{end}}>
+<summary>{{.Keyword}}: {{.Name}} <span class="duration">{{.Duration}}</span></summary>
+{{if .Description}}<p class="description">{{.Description}}</p>
+{{end}}<ul>
+{{range .Steps}}<li class="{{.Result}}">{{.Keyword}}{{.Text}} <span class="duration">{{.Duration}}</span>
+{{- if .Error}}<div class="error">line {{.Line}}: {{.Error}}</div>{{end}}</li>
+{{end}}</ul>
+</details>
+{{end}}
+{{end}}
+</body>
+</html>
+`))
+
+// writeHTMLReport writes results of the executed features to w as a self-contained HTML document
+func writeHTMLReport(w io.Writer, features []*models.Feature) error {
+	scenarios, steps := countResults(features)
+
+	report := htmlReport{
+		Scenarios: summaryLine("scenarios", scenarios, plain),
+		Steps:     summaryLine("steps", steps, plain),
+		Features:  make([]htmlFeature, 0, len(features)),
+	}
+
+	for _, feature := range features {
+		hf := htmlFeature{
+			Keyword:     feature.Keyword,
+			Name:        feature.Name,
+			Description: description(feature.Description),
+			Scenarios:   make([]htmlScenario, 0, len(feature.Scenarios)),
+		}
+
+		for _, scenario := range feature.Scenarios {
+			hs := htmlScenario{
+				Keyword:     scenario.Keyword,
+				Name:        scenario.Name,
+				Description: description(scenario.Description),
+				Result:      scenario.Result().String(),
+				Steps:       make([]htmlStep, 0, len(scenario.Steps)),
+			}
+
+			for _, step := range scenario.Steps {
+				duration := step.Execution.Duration()
+				hs.Duration += duration
+				hs.Steps = append(hs.Steps, htmlStep{
+					Keyword:  step.Keyword,
+					Text:     step.Text,
+					Line:     line(step.Location),
+					Result:   step.Execution.Result.String(),
+					Duration: duration,
+					Error:    errorMessage(step.Execution.Err),
+				})
+			}
+
+			hf.Scenarios = append(hf.Scenarios, hs)
+		}
+
+		report.Features = append(report.Features, hf)
+	}
+
+	return htmlTemplate.Execute(w, report)
+}
@@ -0,0 +1,122 @@
+package gobdd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/go-bdd/gobdd/models"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnitReport writes results of the executed features to w in the JUnit XML format.
+// Every feature is reported as a test suite and every scenario as a test case.
+// Pending scenarios are reported as failures when strict is set and as skipped otherwise
+func writeJUnitReport(w io.Writer, features []*models.Feature, strict bool) error {
+	report := junitTestSuites{}
+	var total time.Duration
+
+	for _, feature := range features {
+		suite := junitTestSuite{Name: feature.Name}
+		var suiteTime time.Duration
+
+		for _, scenario := range feature.Scenarios {
+			testCase := junitTestCase{Name: scenario.Name, ClassName: feature.Name}
+			var caseTime time.Duration
+
+			for _, step := range scenario.Steps {
+				caseTime += step.Execution.Duration()
+
+				result := step.Execution.Result
+				failed := result == models.Failed || result == models.Undefined || (strict && result == models.Pending)
+				if failed && testCase.Failure == nil {
+					message := errorMessage(step.Execution.Err)
+					testCase.Failure = &junitFailure{
+						Message: message,
+						Text:    fmt.Sprintf("%s%s (line %d): %s", step.Keyword, step.Text, line(step.Location), message),
+					}
+				}
+			}
+
+			switch scenario.Result() {
+			case models.Failed, models.Undefined:
+				suite.Failures++
+			case models.Pending:
+				if strict {
+					suite.Failures++
+				} else {
+					testCase.Skipped = &struct{}{}
+					suite.Skipped++
+				}
+			case models.Skipped:
+				testCase.Skipped = &struct{}{}
+				suite.Skipped++
+			}
+
+			testCase.Time = seconds(caseTime)
+			suite.Tests++
+			suite.TestCases = append(suite.TestCases, testCase)
+			suiteTime += caseTime
+		}
+
+		suite.Time = seconds(suiteTime)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Skipped += suite.Skipped
+		report.Suites = append(report.Suites, suite)
+		total += suiteTime
+	}
+
+	report.Time = seconds(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
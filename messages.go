@@ -0,0 +1,210 @@
+package gobdd
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	msgs "github.com/cucumber/messages/go/v21"
+
+	"github.com/go-bdd/gobdd/models"
+)
+
+// messagesEmitter writes Cucumber messages as newline-delimited JSON.
+// All methods are no-ops on a nil emitter
+type messagesEmitter struct {
+	mu    sync.Mutex
+	enc   *json.Encoder
+	newId func() string
+	err   error
+}
+
+func newMessagesEmitter(w io.Writer) *messagesEmitter {
+	if w == nil {
+		return nil
+	}
+
+	return &messagesEmitter{
+		enc:   json.NewEncoder(w),
+		newId: (&msgs.Incrementing{}).NewId,
+	}
+}
+
+// emit writes the envelopes. The first write error is kept and returned by runFinished
+func (e *messagesEmitter) emit(envelopes ...*msgs.Envelope) {
+	for _, envelope := range envelopes {
+		if e.err != nil {
+			return
+		}
+
+		e.err = e.enc.Encode(envelope)
+	}
+}
+
+func (e *messagesEmitter) runStarted() {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.emit(&msgs.Envelope{TestRunStarted: &msgs.TestRunStarted{Timestamp: timestamp(time.Now())}})
+}
+
+func (e *messagesEmitter) gherkinDocument(doc *msgs.GherkinDocument) {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.emit(&msgs.Envelope{GherkinDocument: doc})
+}
+
+// scenarioFinished emits the pickle, the test case and the execution of its steps
+// for a scenario which has just been executed
+func (e *messagesEmitter) scenarioFinished(
+	feature *models.Feature, scenario *msgs.Scenario, result *models.Scenario, start, end time.Time,
+) {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	pickle := &msgs.Pickle{
+		Id:         e.newId(),
+		Uri:        feature.Uri,
+		Name:       scenario.Name,
+		Language:   feature.Language,
+		Steps:      []*msgs.PickleStep{},
+		Tags:       []*msgs.PickleTag{},
+		AstNodeIds: []string{scenario.Id},
+	}
+
+	for _, tag := range scenario.Tags {
+		pickle.Tags = append(pickle.Tags, &msgs.PickleTag{Name: tag.Name, AstNodeId: tag.Id})
+	}
+
+	testCase := &msgs.TestCase{Id: e.newId(), PickleId: pickle.Id, TestSteps: []*msgs.TestStep{}}
+
+	for _, step := range result.Steps {
+		pickleStep := &msgs.PickleStep{
+			Id:         e.newId(),
+			Type:       pickleStepType(step.KeywordType),
+			Text:       step.Text,
+			AstNodeIds: []string{},
+		}
+		if step.Id != "" {
+			pickleStep.AstNodeIds = append(pickleStep.AstNodeIds, step.Id)
+		}
+
+		pickle.Steps = append(pickle.Steps, pickleStep)
+		testCase.TestSteps = append(testCase.TestSteps, &msgs.TestStep{Id: e.newId(), PickleStepId: pickleStep.Id})
+	}
+
+	started := &msgs.TestCaseStarted{
+		Id:         e.newId(),
+		TestCaseId: testCase.Id,
+		Timestamp:  timestamp(start),
+	}
+
+	e.emit(
+		&msgs.Envelope{Pickle: pickle},
+		&msgs.Envelope{TestCase: testCase},
+		&msgs.Envelope{TestCaseStarted: started},
+	)
+
+	for i, step := range result.Steps {
+		execution := step.Execution
+		if execution.Result == models.Skipped {
+			execution.StartTime, execution.EndTime = end, end
+		}
+
+		e.emit(
+			&msgs.Envelope{TestStepStarted: &msgs.TestStepStarted{
+				TestCaseStartedId: started.Id,
+				TestStepId:        testCase.TestSteps[i].Id,
+				Timestamp:         timestamp(execution.StartTime),
+			}},
+			&msgs.Envelope{TestStepFinished: &msgs.TestStepFinished{
+				TestCaseStartedId: started.Id,
+				TestStepId:        testCase.TestSteps[i].Id,
+				TestStepResult:    testStepResult(execution),
+				Timestamp:         timestamp(execution.EndTime),
+			}},
+		)
+	}
+
+	e.emit(&msgs.Envelope{TestCaseFinished: &msgs.TestCaseFinished{
+		TestCaseStartedId: started.Id,
+		Timestamp:         timestamp(end),
+	}})
+}
+
+func (e *messagesEmitter) runFinished(success bool) error {
+	if e == nil {
+		return nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.emit(&msgs.Envelope{TestRunFinished: &msgs.TestRunFinished{
+		Success:   success,
+		Timestamp: timestamp(time.Now()),
+	}})
+
+	return e.err
+}
+
+func pickleStepType(keywordType msgs.StepKeywordType) msgs.PickleStepType {
+	switch keywordType {
+	case msgs.StepKeywordType_CONTEXT:
+		return msgs.PickleStepType_CONTEXT
+	case msgs.StepKeywordType_ACTION:
+		return msgs.PickleStepType_ACTION
+	case msgs.StepKeywordType_OUTCOME:
+		return msgs.PickleStepType_OUTCOME
+	}
+
+	return msgs.PickleStepType_UNKNOWN
+}
+
+func testStepResult(execution models.StepExecution) *msgs.TestStepResult {
+	result := &msgs.TestStepResult{
+		Duration: duration(execution.Duration()),
+		Message:  errorMessage(execution.Err),
+	}
+
+	switch execution.Result {
+	case models.Passed:
+		result.Status = msgs.TestStepResultStatus_PASSED
+	case models.Failed:
+		result.Status = msgs.TestStepResultStatus_FAILED
+	case models.Skipped:
+		result.Status = msgs.TestStepResultStatus_SKIPPED
+	case models.Undefined:
+		result.Status = msgs.TestStepResultStatus_UNDEFINED
+	case models.Pending:
+		result.Status = msgs.TestStepResultStatus_PENDING
+	default:
+		result.Status = msgs.TestStepResultStatus_UNKNOWN
+	}
+
+	return result
+}
+
+func timestamp(t time.Time) *msgs.Timestamp {
+	ts := msgs.GoTimeToTimestamp(t)
+	return &ts
+}
+
+func duration(d time.Duration) *msgs.Duration {
+	md := msgs.GoDurationToDuration(d)
+	return &md
+}
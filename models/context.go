@@ -0,0 +1,32 @@
+package models
+
+import (
+	"context"
+	"io"
+	"os"
+)
+
+// RunContext holds what is shared by all steps of a running scenario
+type RunContext struct {
+	// Out is where steps write their output, os.Stdout by default
+	Out io.Writer
+	// ErrOut is where steps write their errors, os.Stderr by default
+	ErrOut io.Writer
+}
+
+type runContextKey struct{}
+
+// WithRunContext returns a copy of ctx carrying the run context
+func WithRunContext(ctx context.Context, rc *RunContext) context.Context {
+	return context.WithValue(ctx, runContextKey{}, rc)
+}
+
+// RunContextFrom returns the run context carried by ctx.
+// When there's none, the run context writing to the standard output is returned
+func RunContextFrom(ctx context.Context) *RunContext {
+	if rc, ok := ctx.Value(runContextKey{}).(*RunContext); ok {
+		return rc
+	}
+
+	return &RunContext{Out: os.Stdout, ErrOut: os.Stderr}
+}
@@ -3,11 +3,13 @@ package models
 import (
 	"context"
 	"errors"
+	"time"
 
 	messages "github.com/cucumber/messages/go/v21"
 )
 
 type Feature struct {
+	Uri         string                   `json:"uri"`
 	Location    *messages.Location       `json:"location"`
 	Tags        []*messages.Tag          `json:"tags"`
 	Language    string                   `json:"language"`
@@ -21,7 +23,15 @@ type Feature struct {
 }
 
 func NewFeature(featureDoc *messages.Feature, scheme *Scheme) (*Feature, error) {
-	f := &Feature{}
+	f := &Feature{
+		Location:    featureDoc.Location,
+		Tags:        featureDoc.Tags,
+		Language:    featureDoc.Language,
+		Keyword:     featureDoc.Keyword,
+		Name:        featureDoc.Name,
+		Description: featureDoc.Description,
+		Children:    featureDoc.Children,
+	}
 	var rules []*messages.Rule
 	var backgrounds []*messages.Background
 	var scenarios []*messages.Scenario
@@ -65,6 +75,17 @@ func NewFeature(featureDoc *messages.Feature, scheme *Scheme) (*Feature, error)
 	return f, nil
 }
 
+// Duration returns the time between the start of the first executed step of the feature and the end of the last one.
+// Scenarios running in parallel are counted once
+func (f *Feature) Duration() time.Duration {
+	steps := []*Step{}
+	for _, scenario := range f.Scenarios {
+		steps = append(steps, scenario.Steps...)
+	}
+
+	return stepsDuration(steps)
+}
+
 // Add future parallel options
 func (f *Feature) Run(ctx context.Context) {
 	for _, scenario := range f.Scenarios {
@@ -2,6 +2,7 @@ package models
 
 import (
 	"context"
+	"time"
 
 	messages "github.com/cucumber/messages/go/v21"
 )
@@ -14,22 +15,31 @@ type Scenario struct {
 	Description string               `json:"description"`
 	Background  *messages.Background `json:"background"`
 	Steps       []*Step              `json:"steps"`
+	// Attempts is how many times the scenario was run when it was retried after failures
+	Attempts int `json:"attempts,omitempty"`
 	//Examples    []*Examples        `json:"examples"`
 }
 
 func NewScenario(bkg *messages.Background, scn *messages.Scenario, scheme *Scheme) (*Scenario, error) {
 	s := &Scenario{
-		Location:   scn.Location,
-		Tags:       scn.Tags,
-		Keyword:    scn.Keyword,
-		Name:       scn.Name,
-		Background: bkg,
+		Location:    scn.Location,
+		Tags:        scn.Tags,
+		Keyword:     scn.Keyword,
+		Name:        scn.Name,
+		Description: scn.Description,
+		Background:  bkg,
 	}
 
-	bkgSteps, err := GenerateSteps(bkg.Steps, scheme)
+	var bkgStepDocs []*messages.Step
+	if bkg != nil {
+		bkgStepDocs = bkg.Steps
+	}
+
+	bkgSteps, err := GenerateSteps(bkgStepDocs, scheme)
 	if err != nil {
 		return s, err
 	}
+
 	scnSteps, err := GenerateSteps(scn.Steps, scheme)
 	if err != nil {
 		return s, err
@@ -45,13 +55,71 @@ func (s *Scenario) Run(ctx context.Context) {
 	// * Register
 	// * PodSessions
 	// * PortForwarders
-	// * out and errOut Writers
+	// out and errOut writers are carried by the RunContext, see WithRunContext
+	// steps following a failed, undefined or pending step aren't executed
+	stopped := false
 	for _, step := range s.Steps {
+		if stopped {
+			step.Execution.Result = Skipped
+			continue
+		}
+
 		step.Run(ctx)
-		if step.Execution.Result != Passed {
-			break
+		stopped = step.Execution.Result != Passed
+	}
+}
+
+// Result returns Failed when any of the steps failed, Undefined when any of them is undefined,
+// Pending when any of them is pending, Skipped when none of them was executed and Passed otherwise
+func (s *Scenario) Result() Result {
+	result := Skipped
+	for _, step := range s.Steps {
+		switch step.Execution.Result {
+		case Failed:
+			return Failed
+		case Undefined:
+			result = Undefined
+		case Pending:
+			if result != Undefined {
+				result = Pending
+			}
+		case Passed:
+			if result == Skipped {
+				result = Passed
+			}
+		}
+	}
+	return result
+}
+
+// Duration returns the time between the start of the first executed step and the end of the last one.
+// It's zero when none of the steps was executed
+func (s *Scenario) Duration() time.Duration {
+	return stepsDuration(s.Steps)
+}
+
+// stepsDuration returns the time between the earliest start and the latest end of executed steps
+func stepsDuration(steps []*Step) time.Duration {
+	var start, end time.Time
+	for _, step := range steps {
+		if step.Execution.StartTime.IsZero() || step.Execution.EndTime.IsZero() {
+			continue
+		}
+
+		if start.IsZero() || step.Execution.StartTime.Before(start) {
+			start = step.Execution.StartTime
+		}
+
+		if step.Execution.EndTime.After(end) {
+			end = step.Execution.EndTime
 		}
 	}
+
+	if start.IsZero() {
+		return 0
+	}
+
+	return end.Sub(start)
 }
 
 type Background struct {
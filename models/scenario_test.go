@@ -1,23 +1,27 @@
 package models
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"regexp"
+	"time"
 
 	messages "github.com/cucumber/messages/go/v21"
-	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
 
-var _ = Describe("Running Scenarios", func() {
+var _ = ginkgo.Describe("Running Scenarios", func() {
 
-	Context("Running Basic Scenarios", Ordered, func() {
+	ginkgo.Context("Running Basic Scenarios", ginkgo.Ordered, func() {
 		scheme := &Scheme{}
 
-		BeforeAll(func() {
+		ginkgo.BeforeAll(func() {
 			Expect(scheme.Register(basicGoodStep)).Should(Succeed())
 		})
 
-		It("should run a basic good step", func() {
+		ginkgo.It("should run a basic good step", func() {
 
 			backgroundDoc := &messages.Background{
 				Steps: []*messages.Step{
@@ -42,4 +46,91 @@ var _ = Describe("Running Scenarios", func() {
 		})
 	})
 
+	ginkgo.Context("Capturing the Output of Steps", ginkgo.Ordered, func() {
+		scheme := &Scheme{}
+
+		ginkgo.BeforeAll(func() {
+			Expect(scheme.Register(StepDefinition{
+				Expression: regexp.MustCompile("I say (.*)"),
+				Function: func(ctx context.Context, s string) error {
+					_, err := fmt.Fprint(RunContextFrom(ctx).Out, s)
+					return err
+				},
+			})).Should(Succeed())
+		})
+
+		ginkgo.It("should write to the writer of the run context", func() {
+			scenarioDoc := &messages.Scenario{
+				Steps: []*messages.Step{
+					{
+						Text: "I say hello",
+					},
+				},
+			}
+			scenario, err := NewScenario(&messages.Background{}, scenarioDoc, scheme)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			out := &bytes.Buffer{}
+			scenario.Run(WithRunContext(context.TODO(), &RunContext{Out: out, ErrOut: out}))
+			Expect(scenario.Steps[0].Execution.Result).Should(Equal(Passed))
+			Expect(out.String()).Should(Equal("hello"))
+		})
+	})
+
+	ginkgo.Context("Measuring Duration", func() {
+		ginkgo.It("should return the time from the start of the first step to the end of the last one", func() {
+			start := time.Now()
+			scenario := &Scenario{Steps: []*Step{
+				{Execution: StepExecution{StartTime: start, EndTime: start.Add(time.Second)}},
+				{Execution: StepExecution{StartTime: start.Add(2 * time.Second), EndTime: start.Add(3 * time.Second)}},
+				{Execution: StepExecution{Result: Skipped}},
+			}}
+			Expect(scenario.Duration()).Should(Equal(3 * time.Second))
+
+			feature := &Feature{Scenarios: []*Scenario{scenario, {Steps: []*Step{
+				{Execution: StepExecution{StartTime: start.Add(time.Second), EndTime: start.Add(4 * time.Second)}},
+			}}}}
+			Expect(feature.Duration()).Should(Equal(4 * time.Second))
+		})
+
+		ginkgo.It("should be zero when no step was executed", func() {
+			scenario := &Scenario{Steps: []*Step{{Execution: StepExecution{Result: Skipped}}}}
+			Expect(scenario.Duration()).Should(BeZero())
+		})
+	})
+
+	ginkgo.Context("Running Pending Scenarios", ginkgo.Ordered, func() {
+		scheme := &Scheme{}
+
+		ginkgo.BeforeAll(func() {
+			Expect(scheme.Register(basicGoodStep)).Should(Succeed())
+			Expect(scheme.Register(pendingStep)).Should(Succeed())
+		})
+
+		ginkgo.It("should skip steps following a pending step", func() {
+			scenarioDoc := &messages.Scenario{
+				Steps: []*messages.Step{
+					{
+						Text: "a word",
+					},
+					{
+						Text: "pending step",
+					},
+					{
+						Text: "a word",
+					},
+				},
+			}
+			scenario, err := NewScenario(&messages.Background{}, scenarioDoc, scheme)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			scenario.Run(context.TODO())
+			Expect(scenario.Steps[0].Execution.Result).Should(Equal(Passed))
+			Expect(scenario.Steps[1].Execution.Result).Should(Equal(Pending))
+			Expect(scenario.Steps[1].Execution.Err).Should(MatchError(ErrPending))
+			Expect(scenario.Steps[2].Execution.Result).Should(Equal(Skipped))
+			Expect(scenario.Result()).Should(Equal(Pending))
+		})
+	})
+
 })
@@ -7,6 +7,9 @@ import (
 	"reflect"
 	"regexp"
 	"strconv"
+	"strings"
+
+	messages "github.com/cucumber/messages/go/v21"
 )
 
 // matchable errors
@@ -19,8 +22,61 @@ var (
 	ErrTooFewArguments             = errors.New("function has too few arguments for regular expression")
 	ErrTooManyArguments            = errors.New("function has too many arguments for regular expression")
 	ErrNoStepDefFound              = errors.New("cannot find a matching step definition")
+	ErrAmbiguousStepDef            = errors.New("more than one step definition matches")
 )
 
+// StepDefError is returned by StepDefFor when there's no step definition matching the step
+// or there's more than one. It wraps ErrNoStepDefFound or ErrAmbiguousStepDef
+type StepDefError struct {
+	// Step is the text of the step
+	Step string
+	// Location is the location of the step in the feature file
+	Location *messages.Location
+	// Patterns are expressions of all step definitions matching the step
+	Patterns []string
+	Err      error
+}
+
+func (e *StepDefError) Error() string {
+	msg := fmt.Sprintf("%s: %s", e.Step, e.Err)
+	if e.Location != nil {
+		msg = fmt.Sprintf("%s (line %d): %s", e.Step, e.Location.Line, e.Err)
+	}
+
+	if len(e.Patterns) > 0 {
+		msg += ": " + strings.Join(e.Patterns, ", ")
+	}
+
+	return msg
+}
+
+func (e *StepDefError) Unwrap() error {
+	return e.Err
+}
+
+// StepDefErrors aggregates errors of all steps which cannot be matched with a step definition
+type StepDefErrors []*StepDefError
+
+func (e StepDefErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+// Is tells whether any of the aggregated errors matches the target
+func (e StepDefErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
 type StepDefinition struct {
 	Expression *regexp.Regexp
 	Function   interface{}
@@ -62,7 +118,8 @@ func (s *Scheme) Register(sd StepDefinition) error {
 	for i := 1; i < typ.NumIn(); i++ {
 		param := typ.In(i)
 		switch param.Kind() {
-		case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8, reflect.String, reflect.Float64, reflect.Float32:
+		case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8, reflect.String,
+			reflect.Float64, reflect.Float32:
 			continue
 		case reflect.Ptr:
 			switch param.Elem().String() {
@@ -71,7 +128,8 @@ func (s *Scheme) Register(sd StepDefinition) error {
 			case "messages.DataTable":
 				continue
 			default:
-				return fmt.Errorf("%w: the argument %d type %s is not supported", ErrUnsupportedArgumentType, i, param.Elem().String())
+				return fmt.Errorf("%w: the argument %d type %s is not supported",
+					ErrUnsupportedArgumentType, i, param.Elem().String())
 			}
 		case reflect.Slice:
 			switch param {
@@ -110,22 +168,19 @@ func (s *Scheme) Register(sd StepDefinition) error {
 
 // Find a Step function which has a regular expression that matches the text input
 // and same number of arguments, ignoring context and DocString or DataTable
-// as they are not provided via the step text
+// as they are not provided via the step text.
+// A *StepDefError is returned when no step definition or more than one matches the step
 func (s *Scheme) StepDefFor(step *Step) error {
-	var matched bool
-	var input []string
-	var fType reflect.Type
+	var matches []StepDefinition
 
 	for _, sd := range s.stepDefinitions {
 		if !sd.Expression.MatchString(step.Text) {
 			continue
 		}
 
-		input = sd.Expression.FindStringSubmatch(step.Text)
-		matchedInputs := len(input)
+		matchedInputs := len(sd.Expression.FindStringSubmatch(step.Text))
 
-		f := reflect.ValueOf(sd.Function)
-		fType = f.Type()
+		fType := reflect.TypeOf(sd.Function)
 		fArgCount := fType.NumIn() // Ignoring context
 
 		// Ignoring DocString or DataTable
@@ -140,15 +195,29 @@ func (s *Scheme) StepDefFor(step *Step) error {
 		}
 
 		if matchedInputs == fArgCount {
-			step.Func = f
-			matched = true
-			break
+			matches = append(matches, sd)
 		}
 	}
-	if !matched {
-		return ErrNoStepDefFound
+
+	switch len(matches) {
+	case 0:
+		return &StepDefError{Step: step.Text, Location: step.Location, Err: ErrNoStepDefFound}
+	case 1:
+	default:
+		patterns := make([]string, 0, len(matches))
+		for _, sd := range matches {
+			patterns = append(patterns, sd.Expression.String())
+		}
+
+		return &StepDefError{Step: step.Text, Location: step.Location, Patterns: patterns, Err: ErrAmbiguousStepDef}
 	}
 
+	sd := matches[0]
+	input := sd.Expression.FindStringSubmatch(step.Text)
+	step.Func = reflect.ValueOf(sd.Function)
+	step.Pattern = sd.Expression.String()
+	fType := step.Func.Type()
+
 	// Build step.Args from matched regexp values converting to their required type and storing as a reflect.Value
 	// Ingoring first parameter context
 	for i := 1; i < fType.NumIn(); i++ {
@@ -205,7 +274,8 @@ func (s *Scheme) StepDefFor(step *Step) error {
 			case "messages.DataTable":
 				step.Args = append(step.Args, reflect.ValueOf(step.DataTable))
 			default:
-				return fmt.Errorf("%w: the argument %d type %s is not supported", ErrUnsupportedArgumentType, i, param.Elem().String())
+				return fmt.Errorf("%w: the argument %d type %s is not supported",
+					ErrUnsupportedArgumentType, i, param.Elem().String())
 			}
 		case reflect.Slice:
 			switch param {
@@ -2,13 +2,14 @@ package models
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
 	"testing"
 
 	messages "github.com/cucumber/messages/go/v21"
-	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
 
@@ -22,6 +23,13 @@ var basicGoodStep = StepDefinition{
 	},
 }
 
+var pendingStep = StepDefinition{
+	Expression: regexp.MustCompile("pending step"),
+	Function: func(ctx context.Context) error {
+		return ErrPending
+	},
+}
+
 var basicStepWithoutContext = StepDefinition{
 	Expression: regexp.MustCompile("a (.*)"),
 	Function: func(s string) error {
@@ -86,64 +94,64 @@ var basicTooManyArgStep = StepDefinition{
 	},
 }
 
-var _ = Describe("Registering Steps", func() {
+var _ = ginkgo.Describe("Registering Steps", func() {
 
-	Context("Registering Basic Steps", func() {
+	ginkgo.Context("Registering Basic Steps", func() {
 
-		It("should register a basic good step", func() {
+		ginkgo.It("should register a basic good step", func() {
 			scheme := Scheme{}
 			Expect(scheme.Register(basicGoodStep)).Should(Succeed())
 		})
 
-		It("should not register a basic step without a context", func() {
+		ginkgo.It("should not register a basic step without a context", func() {
 			scheme := Scheme{}
 			Expect(scheme.Register(basicStepWithoutContext)).Should(MatchError(ErrMustHaveContext))
 		})
 
-		It("should not register a basic step without any arguments", func() {
+		ginkgo.It("should not register a basic step without any arguments", func() {
 			scheme := Scheme{}
 			Expect(scheme.Register(basicStepWithoutArgs)).Should(MatchError(ErrMustHaveContext))
 		})
 
-		It("should not register a basic step without a function", func() {
+		ginkgo.It("should not register a basic step without a function", func() {
 			scheme := Scheme{}
 			Expect(scheme.Register(basicStepWithoutFunc)).Should(MatchError(ErrStepDefinitionMustHaveFunc))
 		})
 
-		It("should not register a basic step which has too few args for the regular expression", func() {
+		ginkgo.It("should not register a basic step which has too few args for the regular expression", func() {
 			scheme := Scheme{}
 			Expect(scheme.Register(basicStepTooFewArgs)).Should(MatchError(ErrTooFewArguments))
 		})
 
-		It("should not register a basic step which has too many args for the regular expression", func() {
+		ginkgo.It("should not register a basic step which has too many args for the regular expression", func() {
 			scheme := Scheme{}
 			Expect(scheme.Register(basicStepTooManyArgs)).Should(MatchError(ErrTooManyArguments))
 		})
 	})
 
-	Context("Registering Steps with DocString or DataTable Arguments", func() {
+	ginkgo.Context("Registering Steps with DocString or DataTable Arguments", func() {
 
-		It("should register a good step with DocString", func() {
+		ginkgo.It("should register a good step with DocString", func() {
 			scheme := Scheme{}
 			Expect(scheme.Register(basicGoodDocStringStep)).Should(Succeed())
 		})
 
-		It("should register a good step with DataTable", func() {
+		ginkgo.It("should register a good step with DataTable", func() {
 			scheme := Scheme{}
 			Expect(scheme.Register(basicGoodDataTableStep)).Should(Succeed())
 		})
 
-		It("should not register a step with two DocString", func() {
+		ginkgo.It("should not register a step with two DocString", func() {
 			scheme := Scheme{}
 			Expect(scheme.Register(basicTooManyDocStringStep)).Should(MatchError(ErrTooManyArguments))
 		})
 	})
 })
 
-var _ = Describe("Hydrating a Step", func() {
+var _ = ginkgo.Describe("Hydrating a Step", func() {
 
-	Context("Applying a Step Definition to a Step", func() {
-		DescribeTable("Applying a matching step definition to a step",
+	ginkgo.Context("Applying a Step Definition to a Step", func() {
+		ginkgo.DescribeTable("Applying a matching step definition to a step",
 			func(text string, arg interface{}, f interface{}) {
 				var step = &Step{
 					Text: text,
@@ -159,18 +167,21 @@ var _ = Describe("Hydrating a Step", func() {
 				Expect(step.Args).Should(HaveLen(1))
 				Expect(step.Args[0].Interface()).Should(Equal(arg))
 			},
-			Entry("When arg is a string", "a word", "word", func(ctx context.Context, a string) error { return nil }),
-			Entry("When arg is a int", "a 47", 47, func(ctx context.Context, a int) error { return nil }),
-			Entry("When arg is a int8", "a 8", int8(8), func(ctx context.Context, a int8) error { return nil }),
-			Entry("When arg is a int16", "a 16", int16(16), func(ctx context.Context, a int16) error { return nil }),
-			Entry("When arg is a int32", "a 32", int32(32), func(ctx context.Context, a int32) error { return nil }),
-			Entry("When arg is a int64", "a 64", int64(64), func(ctx context.Context, a int64) error { return nil }),
-			Entry("When arg is a float32", "a 3.2", float32(3.2), func(ctx context.Context, a float32) error { return nil }),
-			Entry("When arg is a float64", "a 6.4", float64(6.4), func(ctx context.Context, a float64) error { return nil }),
-			Entry("When arg is a []byte", "a bytes", []byte("bytes"), func(ctx context.Context, a []byte) error { return nil }),
+			ginkgo.Entry("When arg is a string", "a word", "word", func(ctx context.Context, a string) error { return nil }),
+			ginkgo.Entry("When arg is a int", "a 47", 47, func(ctx context.Context, a int) error { return nil }),
+			ginkgo.Entry("When arg is a int8", "a 8", int8(8), func(ctx context.Context, a int8) error { return nil }),
+			ginkgo.Entry("When arg is a int16", "a 16", int16(16), func(ctx context.Context, a int16) error { return nil }),
+			ginkgo.Entry("When arg is a int32", "a 32", int32(32), func(ctx context.Context, a int32) error { return nil }),
+			ginkgo.Entry("When arg is a int64", "a 64", int64(64), func(ctx context.Context, a int64) error { return nil }),
+			ginkgo.Entry("When arg is a float32", "a 3.2", float32(3.2),
+				func(ctx context.Context, a float32) error { return nil }),
+			ginkgo.Entry("When arg is a float64", "a 6.4", float64(6.4),
+				func(ctx context.Context, a float64) error { return nil }),
+			ginkgo.Entry("When arg is a []byte", "a bytes", []byte("bytes"),
+				func(ctx context.Context, a []byte) error { return nil }),
 		)
 
-		DescribeTable("Applying a matching step definition with DocString or DataTable to a step",
+		ginkgo.DescribeTable("Applying a matching step definition with DocString or DataTable to a step",
 			func(arg interface{}, f interface{}) {
 				var step = &Step{
 					Text: "a blah",
@@ -192,11 +203,13 @@ var _ = Describe("Hydrating a Step", func() {
 				Expect(step.Args).Should(HaveLen(2))
 				Expect(step.Args[1].Interface()).Should(Equal(arg))
 			},
-			Entry("When DocString", &messages.DocString{Content: "helloworld"}, func(ctx context.Context, s string, doc *messages.DocString) error { return nil }),
-			Entry("When DataTable", &messages.DataTable{}, func(ctx context.Context, s string, doc *messages.DataTable) error { return nil }),
+			ginkgo.Entry("When DocString", &messages.DocString{Content: "helloworld"},
+				func(ctx context.Context, s string, doc *messages.DocString) error { return nil }),
+			ginkgo.Entry("When DataTable", &messages.DataTable{},
+				func(ctx context.Context, s string, doc *messages.DataTable) error { return nil }),
 		)
 
-		It("should not apply for an invalid type", func() {
+		ginkgo.It("should not apply for an invalid type", func() {
 			var stepDef = StepDefinition{
 				Expression: regexp.MustCompile("a (.*)"),
 				Function:   func(ctx context.Context, m map[string]string) error { return nil },
@@ -204,10 +217,66 @@ var _ = Describe("Hydrating a Step", func() {
 			scheme := Scheme{}
 			Expect(scheme.Register(stepDef)).Should(MatchError(ErrUnsupportedArgumentType))
 		})
+
+		ginkgo.It("should expose the matching pattern", func() {
+			step := &Step{Text: "a word"}
+			scheme := Scheme{}
+			Expect(scheme.Register(basicGoodStep)).Should(Succeed())
+			Expect(scheme.StepDefFor(step)).Should(Succeed())
+			Expect(step.Pattern).Should(Equal("a (.*)"))
+		})
+
+		ginkgo.It("should report a step without a matching step definition", func() {
+			step := &Step{Text: "an undefined step", Location: &messages.Location{Line: 3}}
+			scheme := Scheme{}
+			err := scheme.StepDefFor(step)
+			Expect(err).Should(MatchError(ErrNoStepDefFound))
+			Expect(err).Should(MatchError("an undefined step (line 3): cannot find a matching step definition"))
+		})
+
+		ginkgo.It("should report a step matching more than one step definition", func() {
+			step := &Step{Text: "a word"}
+			scheme := Scheme{}
+			Expect(scheme.Register(basicGoodStep)).Should(Succeed())
+			Expect(scheme.Register(StepDefinition{
+				Expression: regexp.MustCompile("a (word)"),
+				Function:   func(ctx context.Context, s string) error { return nil },
+			})).Should(Succeed())
+
+			err := scheme.StepDefFor(step)
+			Expect(err).Should(MatchError(ErrAmbiguousStepDef))
+
+			var defErr *StepDefError
+			Expect(errors.As(err, &defErr)).Should(BeTrue())
+			Expect(defErr.Patterns).Should(Equal([]string{"a (.*)", "a (word)"}))
+		})
+	})
+
+	ginkgo.Context("Generating Steps", func() {
+		ginkgo.It("should report all undefined steps of a scenario at once", func() {
+			scheme := &Scheme{}
+			Expect(scheme.Register(basicGoodStep)).Should(Succeed())
+
+			scenarioDoc := &messages.Scenario{
+				Steps: []*messages.Step{
+					{Text: "a word", Location: &messages.Location{Line: 3}},
+					{Text: "the first undefined step", Location: &messages.Location{Line: 4}},
+					{Text: "the second undefined step", Location: &messages.Location{Line: 5}},
+				},
+			}
+			_, err := NewScenario(nil, scenarioDoc, scheme)
+			Expect(err).Should(MatchError(ErrNoStepDefFound))
+
+			var defErrs StepDefErrors
+			Expect(errors.As(err, &defErrs)).Should(BeTrue())
+			Expect(defErrs).Should(HaveLen(2))
+			Expect(defErrs[0].Step).Should(Equal("the first undefined step"))
+			Expect(defErrs[1].Step).Should(Equal("the second undefined step"))
+		})
 	})
 })
 
 func TestModels(t *testing.T) {
-	RegisterFailHandler(Fail)
-	RunSpecs(t, "Models Suite")
+	RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Models Suite")
 }
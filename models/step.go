@@ -2,8 +2,10 @@ package models
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"runtime/debug"
 	"time"
 
 	messages "github.com/cucumber/messages/go/v21"
@@ -11,6 +13,7 @@ import (
 
 type Step struct {
 	// Should these if templated by hydrated? yes, (maybe not if inject from previous step?)
+	Id          string                   `json:"id"`
 	Location    *messages.Location       `json:"location"`
 	Keyword     string                   `json:"keyword"`
 	KeywordType messages.StepKeywordType `json:"keywordType,omitempty"`
@@ -21,6 +24,8 @@ type Step struct {
 	// Step Definition
 	Func reflect.Value
 	Args []reflect.Value
+	// Pattern is the expression of the step definition matching the step
+	Pattern string `json:"pattern,omitempty"`
 
 	// Step Result
 	Execution StepExecution `json:"execution"`
@@ -31,16 +36,64 @@ type StepExecution struct {
 	StartTime time.Time
 	EndTime   time.Time
 	Err       error
+	// Stack is the stack trace of the step if it panicked
+	Stack []byte
 }
 
+// Duration returns how long the step was executed. It's zero for steps which weren't executed
+func (e StepExecution) Duration() time.Duration {
+	if e.StartTime.IsZero() || e.EndTime.IsZero() {
+		return 0
+	}
+
+	return e.EndTime.Sub(e.StartTime)
+}
+
+// ErrPending can be returned by a step function which isn't implemented yet.
+// The step is recorded as Pending instead of Failed
+var ErrPending = errors.New("the step is pending")
+
 type Result int
 
 const (
 	Passed Result = iota
 	Failed
 	Skipped
+	Undefined
+	Pending
 )
 
+func (r Result) String() string {
+	switch r {
+	case Passed:
+		return "passed"
+	case Failed:
+		return "failed"
+	case Skipped:
+		return "skipped"
+	case Undefined:
+		return "undefined"
+	case Pending:
+		return "pending"
+	}
+	return "unknown"
+}
+
+// ArgStrings returns arguments of the step function rendered as strings
+func (s *Step) ArgStrings() []string {
+	args := make([]string, 0, len(s.Args))
+	for _, arg := range s.Args {
+		if !arg.IsValid() {
+			args = append(args, "<nil>")
+			continue
+		}
+
+		args = append(args, fmt.Sprint(arg.Interface()))
+	}
+
+	return args
+}
+
 func (s *Step) Run(ctx context.Context) {
 	// ctx is the scenario context
 	// it contains an overall deadline or timeout for feature/scenario
@@ -51,6 +104,7 @@ func (s *Step) Run(ctx context.Context) {
 		if r := recover(); r != nil {
 			s.Execution.Result = Failed
 			s.Execution.Err = fmt.Errorf("%s", r)
+			s.Execution.Stack = debug.Stack()
 		}
 	}()
 
@@ -70,6 +124,10 @@ func (s *Step) Run(ctx context.Context) {
 	r := ret[0].Interface()
 	if err, ok := r.(error); ok {
 		s.Execution.Result = Failed
+		if errors.Is(err, ErrPending) {
+			s.Execution.Result = Pending
+		}
+
 		s.Execution.Err = err
 		return
 	}
@@ -78,6 +136,7 @@ func (s *Step) Run(ctx context.Context) {
 
 func NewStep(stepDoc *messages.Step, scheme *Scheme) (*Step, error) {
 	s := &Step{
+		Id:          stepDoc.Id,
 		Location:    stepDoc.Location,
 		Keyword:     stepDoc.Keyword,
 		KeywordType: stepDoc.KeywordType,
@@ -94,14 +153,40 @@ func NewStep(stepDoc *messages.Step, scheme *Scheme) (*Step, error) {
 	return s, nil
 }
 
+// GenerateSteps matches all steps with step definitions of the scheme.
+// Steps without a matching step definition, or with more than one, are reported together as StepDefErrors
 func GenerateSteps(stepDocs []*messages.Step, scheme *Scheme) ([]*Step, error) {
 	var steps []*Step
+	var defErrs StepDefErrors
+	previous := messages.StepKeywordType_UNKNOWN
 	for _, stepDoc := range stepDocs {
 		step, err := NewStep(stepDoc, scheme)
+		var defErr *StepDefError
+		if errors.As(err, &defErr) {
+			defErrs = append(defErrs, defErr)
+			continue
+		}
 		if err != nil {
 			return nil, err
 		}
+		step.KeywordType = ResolveKeywordType(step.KeywordType, previous)
+		previous = step.KeywordType
 		steps = append(steps, step)
 	}
+	if len(defErrs) > 0 {
+		return nil, defErrs
+	}
 	return steps, nil
 }
+
+// ResolveKeywordType returns the keyword type of a step following a step of the previous type.
+// Conjunctions (And, But) inherit the type of the previous step
+func ResolveKeywordType(keywordType, previous messages.StepKeywordType) messages.StepKeywordType {
+	if keywordType != messages.StepKeywordType_CONJUNCTION {
+		return keywordType
+	}
+	if previous == "" {
+		return messages.StepKeywordType_UNKNOWN
+	}
+	return previous
+}
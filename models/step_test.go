@@ -2,22 +2,24 @@ package models
 
 import (
 	"context"
+	"reflect"
+	"time"
 
 	messages "github.com/cucumber/messages/go/v21"
-	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
 
-var _ = Describe("Running Steps", func() {
+var _ = ginkgo.Describe("Running Steps", func() {
 
-	Context("Running Basic Steps", Ordered, func() {
+	ginkgo.Context("Running Basic Steps", ginkgo.Ordered, func() {
 		scheme := &Scheme{}
 
-		BeforeAll(func() {
+		ginkgo.BeforeAll(func() {
 			Expect(scheme.Register(basicGoodStep)).Should(Succeed())
 		})
 
-		It("should run a basic good step", func() {
+		ginkgo.It("should run a basic good step", func() {
 			stepDoc := &messages.Step{
 				Text: "a word",
 			}
@@ -32,4 +34,25 @@ var _ = Describe("Running Steps", func() {
 		})
 	})
 
+	ginkgo.Context("Measuring Duration", func() {
+		ginkgo.It("should return the duration of the executed step", func() {
+			start := time.Now()
+			execution := StepExecution{StartTime: start, EndTime: start.Add(time.Second)}
+
+			Expect(execution.Duration()).Should(Equal(time.Second))
+		})
+
+		ginkgo.It("should return zero for the step which wasn't executed", func() {
+			Expect(StepExecution{Result: Skipped}.Duration()).Should(BeZero())
+		})
+	})
+
+	ginkgo.Context("Rendering Arguments", func() {
+		ginkgo.It("should render arguments as strings", func() {
+			step := &Step{Args: []reflect.Value{reflect.ValueOf(42), reflect.ValueOf("apples"), {}}}
+
+			Expect(step.ArgStrings()).Should(Equal([]string{"42", "apples", "<nil>"}))
+		})
+	})
+
 })
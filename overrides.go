@@ -0,0 +1,122 @@
+package gobdd
+
+import (
+	"flag"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	tagsFlag     = "gobdd.tags"
+	featuresFlag = "gobdd.features"
+)
+
+// flagsMu guards registration of flags by suites created at the same time
+var flagsMu sync.Mutex
+
+// RegisterFlags registers -gobdd.tags and -gobdd.features used by WithFlagOverrides in the flag set,
+// usually flag.CommandLine. Flags which are already defined in the flag set aren't registered again.
+// To pass the flags to `go test`, register them before flags are parsed, e.g. in TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		gobdd.RegisterFlags(flag.CommandLine)
+//		os.Exit(m.Run())
+//	}
+func RegisterFlags(fs *flag.FlagSet) {
+	flagsMu.Lock()
+	defer flagsMu.Unlock()
+
+	if fs.Lookup(tagsFlag) == nil {
+		fs.String(tagsFlag, "",
+			"comma-separated tags of scenarios to run, e.g. @smoke,@fast. Used by suites created with gobdd.WithFlagOverrides")
+	}
+
+	if fs.Lookup(featuresFlag) == nil {
+		fs.String(featuresFlag, "",
+			"comma-separated paths (glob patterns) of features to run. Used by suites created with gobdd.WithFlagOverrides")
+	}
+}
+
+// flagValue returns the value of the flag of flag.CommandLine, or an empty string when it isn't defined
+func flagValue(name string) string {
+	flagsMu.Lock()
+	defer flagsMu.Unlock()
+
+	if f := flag.CommandLine.Lookup(name); f != nil {
+		return f.Value.String()
+	}
+
+	return ""
+}
+
+// WithFlagOverrides applies values of command-line flags over the other options of the suite,
+// regardless of the order of the options:
+//
+//	go test ./... -gobdd.tags=@smoke -gobdd.features=features/login.feature
+//
+// -gobdd.tags replaces tags configured with WithTags
+// and -gobdd.features replaces paths of features configured with WithFeaturesPath or WithFeaturesFS.
+// Patterns of features are matched in the filesystem of WithFeaturesFS when it's configured.
+// Flags which aren't set don't change the options.
+//
+// The flags are read from flag.CommandLine. The option registers them if they aren't registered yet,
+// but `go test` accepts them only when they're registered with RegisterFlags before flags are parsed
+func WithFlagOverrides() func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		RegisterFlags(flag.CommandLine)
+		options.flagOverrides = true
+	}
+}
+
+// WithTagsFromEnv reads a tag expression from the environment variable, e.g. GOBDD_TAGS="@integration and not @slow".
+// When the variable is set, the expression replaces the one configured with WithTagExpression
+// and tags configured with WithTags, regardless of the order of the options.
+// When the variable is empty or not set, the option does nothing.
+// An invalid expression produces an error and stops executing, like in WithTagExpression
+func WithTagsFromEnv(varName string) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.tagsEnv = varName
+	}
+}
+
+// applyOverrides replaces options with the tag expression from the environment and values of the command-line flags
+func (o *SuiteOptions) applyOverrides() {
+	if o.tagsEnv != "" {
+		if expr := strings.TrimSpace(os.Getenv(o.tagsEnv)); expr != "" {
+			WithTagExpression(expr)(o)
+			o.tags = []string{}
+		}
+	}
+
+	if !o.flagOverrides {
+		return
+	}
+
+	if tags := splitFlag(flagValue(tagsFlag)); len(tags) > 0 {
+		WithTags(tags...)(o)
+	}
+
+	if paths := splitFlag(flagValue(featuresFlag)); len(paths) > 0 {
+		o.features = []string{}
+		o.featureWarnings = []string{}
+
+		if o.featuresFS != nil {
+			WithFeaturesFS(o.featuresFS, paths...)(o)
+		} else {
+			WithFeaturesPath(paths...)(o)
+		}
+	}
+}
+
+// splitFlag splits a comma-separated value of the flag skipping empty items
+func splitFlag(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,118 @@
+package gobdd
+
+import (
+	"context"
+	"flag"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithFlagOverrides(t *testing.T) {
+	RegisterFlags(flag.CommandLine)
+	require.NoError(t, flag.Set("gobdd.tags", "@tag"))
+	require.NoError(t, flag.Set("gobdd.features", "features/tags.feature"))
+	defer func() {
+		require.NoError(t, flag.Set("gobdd.tags", ""))
+		require.NoError(t, flag.Set("gobdd.features", ""))
+	}()
+
+	executed := []string{}
+	suite := NewSuite(WithFlagOverrides(), WithFeaturesPath("features/report.feature"), WithTags("@baked"))
+	suite.AddStep(`the test should pass`, func(ctx context.Context) {
+		executed = append(executed, ScenarioName(ctx))
+	})
+	suite.AddStep(`fail the test`, fail)
+
+	suite.RunWithT(t)
+
+	require.Equal(t, []string{"features/tags.feature"}, suite.options.features)
+	require.Equal(t, []string{"@tag"}, suite.options.tags)
+	require.Equal(t, []string{"the scenario should be pass"}, executed)
+}
+
+func TestWithoutFlagOverrides(t *testing.T) {
+	RegisterFlags(flag.CommandLine)
+	require.NoError(t, flag.Set("gobdd.tags", "@tag"))
+	defer func() {
+		require.NoError(t, flag.Set("gobdd.tags", ""))
+	}()
+
+	suite := NewSuite(WithTags("@baked"))
+
+	require.Equal(t, []string{"@baked"}, suite.options.tags)
+}
+
+func TestFlagOverridesOfFeaturesFS(t *testing.T) {
+	RegisterFlags(flag.CommandLine)
+	require.NoError(t, flag.Set("gobdd.features", "first.feature"))
+	defer func() {
+		require.NoError(t, flag.Set("gobdd.features", ""))
+	}()
+
+	fsys := fstest.MapFS{
+		"first.feature":  {Data: []byte("Feature: first\n  Scenario: first\n    Then the test should pass\n")},
+		"second.feature": {Data: []byte("Feature: second\n  Scenario: second\n    Then the test should pass\n")},
+	}
+
+	executed := []string{}
+	suite := NewSuite(WithFlagOverrides(), WithFeaturesFS(fsys, "*.feature"))
+	suite.AddStep(`the test should pass`, func(ctx context.Context) {
+		executed = append(executed, ScenarioName(ctx))
+	})
+
+	suite.RunWithT(t)
+
+	require.Equal(t, []string{"first.feature"}, suite.options.features)
+	require.NotNil(t, suite.options.featuresFS)
+	require.Equal(t, []string{"first"}, executed)
+}
+
+func TestRegisterFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("gobdd.tags", "@defined", "")
+
+	require.NotPanics(t, func() {
+		RegisterFlags(fs)
+		RegisterFlags(fs)
+	})
+	require.Equal(t, "@defined", fs.Lookup("gobdd.tags").Value.String())
+	require.NotNil(t, fs.Lookup("gobdd.features"))
+}
+
+func TestWithTagsFromEnv(t *testing.T) {
+	testCases := map[string]struct {
+		value    string
+		expected []string
+	}{
+		"expression": {
+			value:    "@smoke and not @wip",
+			expected: []string{"smoke and fast"},
+		},
+		"empty variable": {
+			value:    "",
+			expected: []string{"smoke and wip"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, os.Setenv("GOBDD_TEST_TAGS", testCase.value))
+			defer os.Unsetenv("GOBDD_TEST_TAGS")
+
+			executed := []string{}
+			suite := NewSuite(WithTagsFromEnv("GOBDD_TEST_TAGS"), WithFeaturesPath("features/tag_expressions.feature"),
+				WithTags("@wip"), WithWIPStrict())
+			suite.AddStep(`the "(.*)" scenario runs`, func(_ StepTest, _ context.Context, name string) {
+				executed = append(executed, name)
+			})
+
+			suite.RunWithT(t)
+
+			require.Equal(t, testCase.expected, executed)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package gobdd
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	msgs "github.com/cucumber/messages/go/v21"
+
+	"github.com/go-bdd/gobdd/models"
+)
+
+type jsonFeature struct {
+	Keyword     string         `json:"keyword"`
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Line        int64          `json:"line"`
+	Scenarios   []jsonScenario `json:"scenarios"`
+}
+
+type jsonScenario struct {
+	Keyword     string     `json:"keyword"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Line        int64      `json:"line"`
+	Attempts    int        `json:"attempts,omitempty"`
+	Steps       []jsonStep `json:"steps"`
+}
+
+type jsonStep struct {
+	Keyword  string        `json:"keyword"`
+	Text     string        `json:"text"`
+	Line     int64         `json:"line"`
+	Args     []string      `json:"args,omitempty"`
+	Result   string        `json:"result"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+	Stack    string        `json:"stack,omitempty"`
+}
+
+// writeJSONReport writes results of the executed features to w.
+// The duration of a step is expressed in nanoseconds
+func writeJSONReport(w io.Writer, features []*models.Feature) error {
+	report := make([]jsonFeature, 0, len(features))
+
+	for _, feature := range features {
+		jf := jsonFeature{
+			Keyword:     feature.Keyword,
+			Name:        feature.Name,
+			Description: description(feature.Description),
+			Line:        line(feature.Location),
+			Scenarios:   make([]jsonScenario, 0, len(feature.Scenarios)),
+		}
+
+		for _, scenario := range feature.Scenarios {
+			js := jsonScenario{
+				Keyword:     scenario.Keyword,
+				Name:        scenario.Name,
+				Description: description(scenario.Description),
+				Line:        line(scenario.Location),
+				Attempts:    scenario.Attempts,
+				Steps:       make([]jsonStep, 0, len(scenario.Steps)),
+			}
+
+			for _, step := range scenario.Steps {
+				js.Steps = append(js.Steps, jsonStep{
+					Keyword:  step.Keyword,
+					Text:     step.Text,
+					Line:     line(step.Location),
+					Args:     step.ArgStrings(),
+					Result:   step.Execution.Result.String(),
+					Duration: step.Execution.Duration(),
+					Error:    errorMessage(step.Execution.Err),
+					Stack:    string(step.Execution.Stack),
+				})
+			}
+
+			jf.Scenarios = append(jf.Scenarios, js)
+		}
+
+		report = append(report, jf)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(report)
+}
+
+func line(location *msgs.Location) int64 {
+	if location == nil {
+		return 0
+	}
+
+	return location.Line
+}
+
+// description removes the indentation of lines of the description of a feature or a scenario
+// and leading and trailing empty lines
+func description(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSpace(l)
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func errorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
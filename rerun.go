@@ -0,0 +1,103 @@
+package gobdd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-bdd/gobdd/models"
+)
+
+// WithRerunReport writes locations of failed scenarios to the file at the path after the run,
+// one feature:line per line, so they can be run again with WithRerunFrom.
+// Scenarios with undefined steps are treated as failed,
+// and so are scenarios with pending steps when the suite is run with WithStrict.
+// The file is written, empty, even if no scenario failed
+func WithRerunReport(path string) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.rerunReport = path
+	}
+}
+
+// WithRerunFrom runs only scenarios listed in the file at the path, written by WithRerunReport.
+// It replaces paths of features with features listed in the file and adds line filters for the scenarios,
+// so nothing is executed when the file is empty. It panics when the file cannot be read
+func WithRerunFrom(path string) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		locations, err := readRerunFile(path)
+		if err != nil {
+			panic(fmt.Sprintf("cannot read the rerun file %s: %s", path, err))
+		}
+
+		options.features = []string{}
+		for _, location := range locations {
+			options.features = appendUnique(options.features, location.path)
+			WithLineFilter(location.path, location.line)(options)
+		}
+	}
+}
+
+// rerunLocation is the location of a scenario in the rerun file
+type rerunLocation struct {
+	path string
+	line int
+}
+
+// readRerunFile reads locations of scenarios from the rerun file, skipping empty lines
+func readRerunFile(path string) ([]rerunLocation, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	locations := []rerunLocation{}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		i := strings.LastIndex(text, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("the location %s should be feature:line", text)
+		}
+
+		line, err := strconv.Atoi(text[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("the location %s should be feature:line", text)
+		}
+
+		locations = append(locations, rerunLocation{path: filepath.Clean(text[:i]), line: line})
+	}
+
+	return locations, scanner.Err()
+}
+
+// writeRerunReport writes locations of failed scenarios to the file at the path
+func writeRerunReport(path string, features []*models.Feature, strict bool) error {
+	var b strings.Builder
+
+	for _, feature := range features {
+		for _, scenario := range feature.Scenarios {
+			switch scenario.Result() {
+			case models.Failed, models.Undefined:
+			case models.Pending:
+				if !strict {
+					continue
+				}
+			default:
+				continue
+			}
+
+			fmt.Fprintf(&b, "%s:%d\n", feature.Uri, line(scenario.Location))
+		}
+	}
+
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
@@ -0,0 +1,56 @@
+package gobdd
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRerunFailedScenarios(t *testing.T) {
+	rerunFile := filepath.Join(t.TempDir(), "rerun.txt")
+
+	newSuite := func(executed *[]string, options ...func(*SuiteOptions)) *Suite {
+		suite := NewSuite(append(options, WithOutput(io.Discard))...)
+		suite.AddStep(`the step passes`, func(ctx context.Context) {
+			*executed = append(*executed, ScenarioName(ctx))
+		})
+		suite.AddStep(`the step fails`, failure)
+
+		return suite
+	}
+
+	executed := []string{}
+	require.NoError(t, newSuite(&executed, WithFeaturesPath("features/report.feature"), WithRerunReport(rerunFile)).Run())
+	require.Contains(t, executed, "the passing scenario")
+
+	content, err := os.ReadFile(rerunFile)
+	require.NoError(t, err)
+	require.Equal(t, "features/report.feature:5\n", string(content))
+
+	executed = []string{}
+	require.NoError(t, newSuite(&executed, WithRerunFrom(rerunFile)).Run())
+	require.Equal(t, []string{"the failing scenario"}, executed)
+}
+
+func TestRerunFromEmptyFile(t *testing.T) {
+	rerunFile := filepath.Join(t.TempDir(), "rerun.txt")
+	require.NoError(t, os.WriteFile(rerunFile, nil, 0o644))
+
+	suite := NewSuite(WithFeaturesPath("features/report.feature"), WithRerunFrom(rerunFile), WithOutput(io.Discard))
+	suite.AddStep(`the step passes`, pass)
+
+	scenarios, err := suite.RunCollect()
+
+	require.NoError(t, err)
+	require.Empty(t, scenarios)
+}
+
+func TestWithRerunFromPanicsOnMissingFile(t *testing.T) {
+	require.Panics(t, func() {
+		NewSuite(WithRerunFrom(filepath.Join(t.TempDir(), "missing.txt")))
+	})
+}
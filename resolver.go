@@ -0,0 +1,77 @@
+package gobdd
+
+import (
+	"fmt"
+	"regexp"
+
+	msgs "github.com/cucumber/messages/go/v21"
+)
+
+// StepResolver finds the step function for the text of a step, replacing matching of steps added to the suite,
+// e.g. to match steps fuzzily or to load mappings of steps from a file.
+//
+// Resolve returns an error when there's no step function for the text, so the step is reported as undefined
+type StepResolver interface {
+	Resolve(text string) (ResolvedStep, error)
+}
+
+// ResolvedStep is the step function found by a StepResolver
+type ResolvedStep struct {
+	// Expr is matched with the text of the step and its capturing groups are passed as arguments
+	// of the step function, like expressions of steps added with AddStep.
+	// When it's nil, the step function gets no arguments
+	Expr *regexp.Regexp
+	// Step is the step function, accepting the same arguments as functions added with AddStep
+	Step interface{}
+}
+
+// WithStepResolver replaces matching of steps added to the suite with the resolver.
+// Parameter types and keywords of steps aren't used, while transforms and the list separator of the suite are.
+// A step fails when the resolved step function doesn't accept arguments of its expression
+func WithStepResolver(r StepResolver) func(*SuiteOptions) {
+	return func(options *SuiteOptions) {
+		options.stepResolver = r
+	}
+}
+
+// resolveStep finds the step definition for the text with the resolver of the suite,
+// or among steps added to the suite when there's no resolver
+func (s *Suite) resolveStep(text string, keyword msgs.StepKeywordType) (stepDef, error) {
+	if s.options.stepResolver == nil {
+		return s.findStepDef(text, keyword)
+	}
+
+	resolved, err := s.options.stepResolver.Resolve(text)
+	if err != nil {
+		return stepDef{}, err
+	}
+
+	expr := resolved.Expr
+	if expr == nil {
+		expr = regexp.MustCompile(``)
+	}
+
+	if !expr.MatchString(text) {
+		return stepDef{}, fmt.Errorf("the expression %s of the resolved step doesn't match the step", expr)
+	}
+
+	if err := validateStepFunc(resolved.Step); err != nil {
+		return stepDef{}, &invalidStepError{msg: fmt.Sprintf("the resolved step function is incorrect: %s", err)}
+	}
+
+	if err := validateStepArgs(expr, resolved.Step, s.transforms); err != nil {
+		return stepDef{}, &invalidStepError{msg: fmt.Sprintf("the resolved step function is incorrect: %s", err)}
+	}
+
+	return s.newStepDef(expr, resolved.Step, &stepUsage{expr: expr.String()}, ""), nil
+}
+
+// invalidStepError is returned when the resolved step function doesn't accept arguments of its expression,
+// so the step fails instead of being undefined
+type invalidStepError struct {
+	msg string
+}
+
+func (e *invalidStepError) Error() string {
+	return e.msg
+}
@@ -0,0 +1,97 @@
+package gobdd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-bdd/gobdd/models"
+)
+
+type resolverFunc func(text string) (ResolvedStep, error)
+
+func (f resolverFunc) Resolve(text string) (ResolvedStep, error) {
+	return f(text)
+}
+
+func TestWithStepResolver(t *testing.T) {
+	recorded := []string{}
+	resolver := resolverFunc(func(text string) (ResolvedStep, error) {
+		return ResolvedStep{Step: func(ctx context.Context) {
+			recorded = append(recorded, CurrentStep(ctx).Text)
+		}}, nil
+	})
+
+	suite := NewSuite(WithStepResolver(resolver), WithInlineFeature("resolver.feature", `Feature: resolver
+  Scenario: any step
+    Given anything at all
+    When something happens
+`))
+	suite.AddStep(`anything at all`, fail)
+
+	suite.RunWithT(t)
+
+	require.Equal(t, []string{"anything at all", "something happens"}, recorded)
+}
+
+func TestWithStepResolverArguments(t *testing.T) {
+	var sum int
+	resolver := resolverFunc(func(text string) (ResolvedStep, error) {
+		if !strings.HasPrefix(text, "I add") {
+			return ResolvedStep{}, errors.New("unknown step")
+		}
+
+		return ResolvedStep{
+			Expr: regexp.MustCompile(`I add (\d+) and (\d+)`),
+			Step: func(ctx context.Context, a, b int) {
+				sum = a + b
+			},
+		}, nil
+	})
+
+	suite := NewSuite(WithStepResolver(resolver), WithOutput(io.Discard), WithUndefinedStepSnippets(io.Discard),
+		WithInlineFeature("resolver.feature", `Feature: resolver
+  Scenario: resolved arguments
+    When I add 2 and 3
+    Then the step is unknown
+`))
+
+	scenarios, err := suite.RunCollect()
+
+	require.NoError(t, err)
+	require.Equal(t, 5, sum)
+	require.Equal(t, models.Passed, scenarios[0].Steps[0].Result)
+	require.Equal(t, models.Undefined, scenarios[0].Steps[1].Result)
+}
+
+func TestWithStepResolverInvalidStepFunction(t *testing.T) {
+	resolver := resolverFunc(func(text string) (ResolvedStep, error) {
+		return ResolvedStep{
+			Expr: regexp.MustCompile(`I add (\d+) and (\d+)`),
+			Step: func(ctx context.Context, a int) {},
+		}, nil
+	})
+
+	suite := NewSuite(WithStepResolver(resolver), WithOutput(io.Discard),
+		WithInlineFeature("resolver.feature", `Feature: resolver
+  Scenario: invalid step function
+    When I add 2 and 3
+`))
+
+	var scenarios []CollectedScenario
+	var err error
+	require.NotPanics(t, func() {
+		scenarios, err = suite.RunCollect()
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, models.Failed, scenarios[0].Steps[0].Result)
+	require.EqualError(t, scenarios[0].Steps[0].Err,
+		"the resolved step function is incorrect: the expression I add (\\d+) and (\\d+) has 2 capturing groups "+
+			"but the function accepts 1 arguments")
+}
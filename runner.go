@@ -0,0 +1,157 @@
+package gobdd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-bdd/gobdd/models"
+)
+
+// Runner runs several suites, e.g. suites with different steps in one test package, and combines their results.
+//
+// Command-line flags used by WithFlagOverrides are shared by all suites, see RegisterFlags
+type Runner struct {
+	suites  []*Suite
+	options RunnerOptions
+}
+
+// RunnerOptions holds the configuration of a runner
+type RunnerOptions struct {
+	parallel bool
+	output   io.Writer
+}
+
+// RunSuitesInParallel runs suites of the runner at the same time, every suite in its own goroutine
+func RunSuitesInParallel() func(*RunnerOptions) {
+	return func(options *RunnerOptions) {
+		options.parallel = true
+	}
+}
+
+// WithRunnerOutput configures the writer shared by all suites of the runner instead of the outputs of the suites.
+// Writes of suites running in parallel don't interleave within a single write
+func WithRunnerOutput(w io.Writer) func(*RunnerOptions) {
+	return func(options *RunnerOptions) {
+		options.output = &syncWriter{w: w}
+	}
+}
+
+// NewRunner creates a runner without suites
+func NewRunner(optionClosures ...func(*RunnerOptions)) *Runner {
+	r := &Runner{}
+	for _, option := range optionClosures {
+		option(&r.options)
+	}
+
+	return r
+}
+
+// Add adds suites to the runner. When the runner has a shared output, it replaces outputs of the suites
+func (r *Runner) Add(suites ...*Suite) {
+	for _, suite := range suites {
+		if r.options.output != nil {
+			suite.options.output = r.options.output
+		}
+
+		r.suites = append(r.suites, suite)
+	}
+}
+
+// Run executes all suites like Suite.Run and returns the summary of results of all of them.
+// The returned error describes errors of all suites which failed to run
+func (r *Runner) Run() (Summary, error) {
+	start := time.Now()
+	errs := make([]error, len(r.suites))
+	r.each(func(i int, suite *Suite) {
+		errs[i] = suite.Run()
+	})
+
+	problems := []string{}
+	for i, err := range errs {
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("suite %d: %s", i+1, err))
+		}
+	}
+
+	summary := r.summary()
+	summary.Duration = time.Since(start)
+
+	if len(problems) > 0 {
+		return summary, fmt.Errorf("%d suites failed to run: %s", len(problems), strings.Join(problems, "; "))
+	}
+
+	return summary, nil
+}
+
+// RunWithT executes every suite as a subtest of t named "suite 1", "suite 2" and so on
+func (r *Runner) RunWithT(t *testing.T) {
+	t.Run("suites", func(t *testing.T) {
+		for i, suite := range r.suites {
+			suite := suite
+			t.Run(fmt.Sprintf("suite %d", i+1), func(t *testing.T) {
+				if r.options.parallel {
+					t.Parallel()
+				}
+
+				suite.RunWithT(t)
+			})
+		}
+	})
+}
+
+// each calls f for every suite, at the same time when the runner runs suites in parallel
+func (r *Runner) each(f func(i int, suite *Suite)) {
+	if !r.options.parallel {
+		for i, suite := range r.suites {
+			f(i, suite)
+		}
+
+		return
+	}
+
+	wg := sync.WaitGroup{}
+	for i, suite := range r.suites {
+		wg.Add(1)
+
+		go func(i int, suite *Suite) {
+			defer wg.Done()
+			f(i, suite)
+		}(i, suite)
+	}
+
+	wg.Wait()
+}
+
+// summary combines results of the last run of all suites.
+// Pending scenarios fail the combined summary when any of the suites is run with WithStrict
+func (r *Runner) summary() Summary {
+	features := []*models.Feature{}
+	strict := false
+
+	for _, suite := range r.suites {
+		suite.mu.RLock()
+		features = append(features, suite.results...)
+		suite.mu.RUnlock()
+
+		strict = strict || suite.options.strict
+	}
+
+	return newSummary(features, strict)
+}
+
+// syncWriter serializes writes of suites sharing the writer
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.w.Write(p)
+}
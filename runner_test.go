@@ -0,0 +1,74 @@
+package gobdd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newRunnerSuites() (*Suite, *Suite) {
+	math := NewSuite(WithInlineFeature("math.feature", `Feature: math
+  Scenario: adding
+    When I add 1 and 2
+    Then the result should equal 3
+
+  Scenario: adding wrong
+    When I add 1 and 2
+    Then the result should equal 4
+`))
+	math.AddStep(`I add (\d+) and (\d+)`, add)
+	math.AddStep(`the result should equal (\d+)`, check)
+
+	words := NewSuite(WithInlineFeature("words.feature", `Feature: words
+  Scenario: saying words
+    When I say "hello"
+`))
+	words.AddStep(`I say {string}`, func(ctx context.Context, word string) {})
+
+	return math, words
+}
+
+func TestRunner(t *testing.T) {
+	testCases := map[string][]func(*RunnerOptions){
+		"sequential": {},
+		"parallel":   {RunSuitesInParallel()},
+	}
+
+	for name, options := range testCases {
+		t.Run(name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			runner := NewRunner(append(options, WithRunnerOutput(output))...)
+			runner.Add(newRunnerSuites())
+
+			summary, err := runner.Run()
+
+			require.NoError(t, err)
+			require.Equal(t, 2, summary.Features)
+			require.Equal(t, ResultCounts{Passed: 2, Failed: 1}, summary.Scenarios)
+			require.Equal(t, ResultCounts{Passed: 4, Failed: 1}, summary.Steps)
+			require.False(t, summary.Succeeded())
+			require.Greater(t, int64(summary.Duration), int64(0))
+			require.Contains(t, output.String(), "2 scenarios (1 passed, 1 failed)")
+			require.Contains(t, output.String(), "1 scenarios (1 passed)")
+		})
+	}
+}
+
+func TestRunnerRunWithT(t *testing.T) {
+	words := NewSuite(WithInlineFeature("words.feature", `Feature: words
+  Scenario: saying words
+    When I say "hello"
+`))
+	said := []string{}
+	words.AddStep(`I say {string}`, func(ctx context.Context, word string) {
+		said = append(said, word)
+	})
+
+	runner := NewRunner(RunSuitesInParallel())
+	runner.Add(words)
+	runner.RunWithT(t)
+
+	require.Equal(t, []string{"hello"}, said)
+}
@@ -0,0 +1,112 @@
+package gobdd
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+
+	msgs "github.com/cucumber/messages/go/v21"
+)
+
+// snippetParam matches quoted strings and numbers in the text of an undefined step
+var snippetParam = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'|-?\d+(\.\d+)?`)
+
+// snippet is a ready-to-paste definition of an undefined step
+type snippet struct {
+	name    string
+	pattern string
+	args    []string
+}
+
+func newSnippet(step *msgs.Step) snippet {
+	sn := snippet{}
+	pattern := strings.Builder{}
+	name := strings.Builder{}
+	last := 0
+
+	for _, m := range snippetParam.FindAllStringIndex(step.Text, -1) {
+		param, arg := "{int}", "int"
+
+		switch value := step.Text[m[0]:m[1]]; {
+		case strings.HasPrefix(value, `"`) || strings.HasPrefix(value, `'`):
+			param, arg = "{text}", "string"
+		case strings.Contains(value, "."):
+			param, arg = "{float}", "float64"
+		}
+
+		pattern.WriteString(regexp.QuoteMeta(step.Text[last:m[0]]))
+		pattern.WriteString(param)
+		name.WriteString(step.Text[last:m[0]])
+		name.WriteString(" ")
+		sn.args = append(sn.args, fmt.Sprintf("arg%d %s", len(sn.args)+1, arg))
+		last = m[1]
+	}
+
+	pattern.WriteString(regexp.QuoteMeta(step.Text[last:]))
+	name.WriteString(step.Text[last:])
+
+	switch {
+	case step.DataTable != nil:
+		sn.args = append(sn.args, "table *gobdd.Table")
+	case step.DocString != nil:
+		sn.args = append(sn.args, "doc *gobdd.DocString")
+	}
+
+	sn.pattern = pattern.String()
+	sn.name = snippetFuncName(name.String())
+
+	return sn
+}
+
+// snippetFuncName converts the text to a camel-cased function name
+func snippetFuncName(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	name := strings.Builder{}
+	for i, word := range words {
+		runes := []rune(strings.ToLower(word))
+		if i > 0 {
+			runes[0] = unicode.ToUpper(runes[0])
+		}
+
+		name.WriteString(string(runes))
+	}
+
+	if name.Len() == 0 || unicode.IsDigit([]rune(name.String())[0]) {
+		return "step" + name.String()
+	}
+
+	return name.String()
+}
+
+func (sn snippet) String() string {
+	args := append([]string{"t gobdd.StepTest", "ctx context.Context"}, sn.args...)
+
+	return fmt.Sprintf("func %s(%s) {\n\tt.Fatal(\"not implemented\")\n}\n\nsuite.AddStep(`%s`, %s)\n",
+		sn.name, strings.Join(args, ", "), sn.pattern, sn.name)
+}
+
+// writeSnippets writes snippets of the undefined steps, every unique pattern only once
+func writeSnippets(w io.Writer, steps []*msgs.Step) {
+	if len(steps) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "You can implement undefined steps with these snippets:")
+
+	seen := map[string]bool{}
+	for _, step := range steps {
+		sn := newSnippet(step)
+		if seen[sn.pattern] {
+			continue
+		}
+
+		seen[sn.pattern] = true
+
+		fmt.Fprintf(w, "\n%s", sn)
+	}
+}
@@ -0,0 +1,54 @@
+package gobdd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	msgs "github.com/cucumber/messages/go/v21"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnippet(t *testing.T) {
+	testCases := []struct {
+		step     *msgs.Step
+		expected string
+	}{
+		{
+			step: &msgs.Step{Text: "I have 3 cukes"},
+			expected: "func iHaveCukes(t gobdd.StepTest, ctx context.Context, arg1 int) {\n" +
+				"\tt.Fatal(\"not implemented\")\n}\n\n" +
+				"suite.AddStep(`I have {int} cukes`, iHaveCukes)\n",
+		},
+		{
+			step: &msgs.Step{Text: `I pay 2.5 for "the 3 apples"`},
+			expected: "func iPayFor(t gobdd.StepTest, ctx context.Context, arg1 float64, arg2 string) {\n" +
+				"\tt.Fatal(\"not implemented\")\n}\n\n" +
+				"suite.AddStep(`I pay {float} for {text}`, iPayFor)\n",
+		},
+		{
+			step: &msgs.Step{Text: "the following users (admins):", DataTable: &msgs.DataTable{}},
+			expected: "func theFollowingUsersAdmins(t gobdd.StepTest, ctx context.Context, table *gobdd.Table) {\n" +
+				"\tt.Fatal(\"not implemented\")\n}\n\n" +
+				"suite.AddStep(`the following users \\(admins\\):`, theFollowingUsersAdmins)\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.step.Text, func(t *testing.T) {
+			require.Equal(t, testCase.expected, newSnippet(testCase.step).String())
+		})
+	}
+}
+
+func TestWithUndefinedStepSnippets(t *testing.T) {
+	out := &bytes.Buffer{}
+	suite := NewSuite(WithFeaturesPath("features/undefined.feature"), WithUndefinedStepSnippets(out))
+	suite.AddStep(`I have {int} cukes`, func(_ StepTest, _ context.Context, _ int) {})
+
+	suite.Run()
+
+	require.Equal(t, "You can implement undefined steps with these snippets:\n\n"+
+		"func iEatCukes(t gobdd.StepTest, ctx context.Context, arg1 int) {\n\tt.Fatal(\"not implemented\")\n}\n\n"+
+		"suite.AddStep(`I eat {int} cukes`, iEatCukes)\n", out.String())
+}
@@ -3,7 +3,23 @@ package gobdd
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"time"
+	"unicode"
+
+	msgs "github.com/cucumber/messages/go/v21"
+)
+
+var (
+	tableType     = reflect.TypeOf((*Table)(nil))
+	docStringType = reflect.TypeOf((*DocString)(nil))
+	stepTestType  = reflect.TypeOf((*StepTest)(nil)).Elem()
+	contextType   = reflect.TypeOf((*context.Context)(nil)).Elem()
+	durationType  = reflect.TypeOf(time.Duration(0))
 )
 
 func validateStepFunc(f interface{}) error {
@@ -12,16 +28,242 @@ func validateStepFunc(f interface{}) error {
 		return errors.New("the parameter should be a function")
 	}
 
-	if value.Type().NumIn() < 1 {
-		return errors.New("the function should have Context as the first argument")
+	for i := 0; i < value.Type().NumOut(); i++ {
+		if value.Type().Out(i) != contextType {
+			return errors.New("the function can only return a Context")
+		}
 	}
 
-	val := value.Type().In(0)
+	for i := argsOffset(value.Type()); i < value.Type().NumIn(); i++ {
+		in := value.Type().In(i)
+		if in == contextType {
+			return errors.New("the Context has to be the first argument of the function or follow the StepTest")
+		}
 
-	testingInterface := reflect.TypeOf((*context.Context)(nil)).Elem()
-	if !val.Implements(testingInterface) {
-		return errors.New("the function should have Context as the first argument")
+		if (in == tableType || in == docStringType) && i < value.Type().NumIn()-1 {
+			return errors.New("the Table or DocString has to be the last argument of the function")
+		}
 	}
 
 	return nil
 }
+
+// validateStepArgs checks whether capturing groups of the expression match arguments of the step function
+func validateStepArgs(expr *regexp.Regexp, f interface{}, transforms map[string]transform) error {
+	d := reflect.TypeOf(f)
+
+	offset := argsOffset(d)
+	args := d.NumIn() - offset
+	indexes := argumentGroups(expr, d)
+	groups := len(indexes)
+
+	if args == 1 && hasNamedGroups(expr, transforms) {
+		if in := d.In(offset); isStringMap(in) || in.Kind() == reflect.Struct {
+			return nil
+		}
+	}
+
+	// the trailing string argument receives the doc string, which is checked when the step is matched
+	if args == groups+1 {
+		if last := d.In(d.NumIn() - 1); last == tableType || last == docStringType || last.Kind() == reflect.String {
+			args--
+		}
+	}
+
+	if args != groups {
+		return fmt.Errorf("the expression %s has %d capturing groups but the function accepts %d arguments",
+			expr, groups, args)
+	}
+
+	names := expr.SubexpNames()
+	for j, i := range indexes {
+		if _, ok := transforms[names[i+1]]; ok {
+			continue
+		}
+
+		if in := d.In(j + offset); !isSupportedParamType(in) {
+			return fmt.Errorf("the argument %d of the function has unsupported type %s", j+offset, in)
+		}
+	}
+
+	return nil
+}
+
+// argumentGroups returns indexes of capturing groups of the expression which are passed to the step function.
+// When the function accepts fewer arguments than there are groups, optional groups (e.g. apple(s)?) are skipped
+func argumentGroups(expr *regexp.Regexp, f reflect.Type) []int {
+	if expr == nil {
+		return []int{}
+	}
+
+	optional := optionalGroups(expr)
+	skipOptional := f.NumIn()-argsOffset(f) < expr.NumSubexp()
+
+	groups := make([]int, 0, expr.NumSubexp())
+	for i := 0; i < expr.NumSubexp(); i++ {
+		if skipOptional && optional[i+1] {
+			continue
+		}
+
+		groups = append(groups, i)
+	}
+
+	return groups
+}
+
+// optionalGroups returns numbers of capturing groups which can match nothing
+// because they are (or are nested in) a part of the expression repeated zero or more times
+func optionalGroups(expr *regexp.Regexp) map[int]bool {
+	optional := map[int]bool{}
+
+	re, err := syntax.Parse(expr.String(), syntax.Perl)
+	if err != nil {
+		return optional
+	}
+
+	var walk func(re *syntax.Regexp, inOptional bool)
+	walk = func(re *syntax.Regexp, inOptional bool) {
+		switch re.Op {
+		case syntax.OpQuest, syntax.OpStar:
+			inOptional = true
+		case syntax.OpRepeat:
+			inOptional = inOptional || re.Min == 0
+		case syntax.OpCapture:
+			if inOptional {
+				optional[re.Cap] = true
+			}
+		}
+
+		for _, sub := range re.Sub {
+			walk(sub, inOptional)
+		}
+	}
+	walk(re, false)
+
+	return optional
+}
+
+// normalizeWhitespace trims the text and collapses runs of whitespace into a single space,
+// except for whitespace inside single or double quotes
+func normalizeWhitespace(text string) string {
+	var b strings.Builder
+	var quote rune
+	space := false
+
+	for _, r := range strings.TrimSpace(text) {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case unicode.IsSpace(r):
+			space = true
+			continue
+		}
+
+		if space {
+			b.WriteRune(' ')
+			space = false
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// isSupportedParamType tells whether a captured value can be converted to the type
+func isSupportedParamType(in reflect.Type) bool {
+	switch in.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return reflect.TypeOf([]byte{}).AssignableTo(in) || isListParamType(in)
+}
+
+// isListParamType tells whether the type is a slice, other than []byte,
+// of types which captured values can be converted to.
+// Captured values are split into elements of the slice with the list separator of the suite
+func isListParamType(in reflect.Type) bool {
+	if in.Kind() != reflect.Slice {
+		return false
+	}
+
+	switch in.Elem().Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+// hasNamedGroups tells whether the expression has named capturing groups
+// other than groups of parameter types with transforms
+func hasNamedGroups(expr *regexp.Regexp, transforms map[string]transform) bool {
+	for _, name := range expr.SubexpNames()[1:] {
+		if _, isTransform := transforms[name]; name != "" && !isTransform {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isStringMap tells whether the type is a map[string]string
+func isStringMap(in reflect.Type) bool {
+	return in.Kind() == reflect.Map && in.Key().Kind() == reflect.String && in.Elem().Kind() == reflect.String
+}
+
+// acceptsStepTest tells whether the step function expects gobdd.StepTest as the first argument
+func acceptsStepTest(f reflect.Type) bool {
+	return f.NumIn() > 0 && f.In(0) == stepTestType
+}
+
+// acceptsContext tells whether the step function expects context.Context
+// as the first argument or right after gobdd.StepTest
+func acceptsContext(f reflect.Type) bool {
+	first := 0
+	if acceptsStepTest(f) {
+		first = 1
+	}
+
+	return f.NumIn() > first && f.In(first).Implements(contextType)
+}
+
+// argsOffset returns the index of the first argument of the step function filled with values of the step
+func argsOffset(f reflect.Type) int {
+	offset := 0
+	if acceptsStepTest(f) {
+		offset++
+	}
+
+	if acceptsContext(f) {
+		offset++
+	}
+
+	return offset
+}
+
+// stepArgument returns the data table or the doc string of the step
+// if the step function expects it right after the other arguments
+func stepArgument(f reflect.Type, step *msgs.Step, args int) (reflect.Value, bool) {
+	if step == nil || f.NumIn() != args+1 {
+		return reflect.Value{}, false
+	}
+
+	switch last := f.In(f.NumIn() - 1); {
+	case last == tableType:
+		return reflect.ValueOf(newTable(step.DataTable)), true
+	case last == docStringType:
+		return reflect.ValueOf(newDocString(step.DocString)), true
+	case last.Kind() == reflect.String && step.DocString != nil:
+		return reflect.ValueOf(step.DocString.Content), true
+	}
+
+	return reflect.Value{}, false
+}
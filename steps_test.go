@@ -3,15 +3,13 @@ package gobdd
 import (
 	"context"
 	"testing"
-
-	"github.com/go-bdd/gobdd"
 )
 
 func TestValidateStepFunc(t *testing.T) {
 	testCases := map[string]interface{}{
-		"function without arguments":           func() {},
-		"function with 1 argument":             func(StepTest) {},
-		"function with invalid first argument": func(int, Context) {},
+		"function with invalid first argument":   func(int, context.Context) {},
+		"function with Table not being last":     func(context.Context, *Table, int) {},
+		"function with DocString not being last": func(context.Context, *DocString, int) {},
 	}
 
 	for name, testCase := range testCases {
@@ -35,7 +33,7 @@ func TestValidateStepFunc_Context(t *testing.T) {
 
 	for name, testCase := range testCases {
 		t.Run(name, func(t *testing.T) {
-			if err := gobdd.ValidateStepFunc(testCase); err == nil {
+			if err := ValidateStepFunc(testCase); err == nil {
 				t.Errorf("the test should fail for the function")
 			}
 		})
@@ -43,14 +41,59 @@ func TestValidateStepFunc_Context(t *testing.T) {
 }
 
 func TestValidateStepFunc_ValidFunction_Context(t *testing.T) {
-	if err := gobdd.ValidateStepFunc(func(context.Context) {}); err != nil {
+	if err := ValidateStepFunc(func(context.Context) {}); err != nil {
 		t.Errorf("the test should NOT fail for the function: %s", err)
 	}
 }
 
+func TestValidateStepFunc_WithoutContext(t *testing.T) {
+	testCases := map[string]interface{}{
+		"function without arguments":         func() {},
+		"function with StepTest only":        func(StepTest) {},
+		"function with arguments only":       func(int, string) {},
+		"function with StepTest and a Table": func(StepTest, *Table) {},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if err := validateStepFunc(testCase); err != nil {
+				t.Errorf("the test should NOT fail for the function: %s", err)
+			}
+		})
+	}
+}
+
 func TestValidateStepFunc_ReturnContext_Context(t *testing.T) {
-	err := gobdd.ValidateStepFunc(func(gobdd.StepTest, context.Context) context.Context { return context.Context{} })
+	err := ValidateStepFunc(func(_ StepTest, ctx context.Context) context.Context { return ctx })
 	if err != nil {
 		t.Errorf("step function returning a context should NOT fail validation: %s", err)
 	}
 }
+
+func TestValidateStepFunc_Table(t *testing.T) {
+	if err := ValidateStepFunc(func(context.Context, string, *Table) {}); err != nil {
+		t.Errorf("the test should NOT fail for the function: %s", err)
+	}
+}
+
+func TestValidateStepFunc_DocString(t *testing.T) {
+	if err := ValidateStepFunc(func(context.Context, string, *DocString) {}); err != nil {
+		t.Errorf("the test should NOT fail for the function: %s", err)
+	}
+}
+
+func TestNormalizeWhitespace(t *testing.T) {
+	testCases := map[string]string{
+		"I add 1 and 2":                "I add 1 and 2",
+		"  I add 1   and\t2  ":         "I add 1 and 2",
+		`I say "hello   world"  twice`: `I say "hello   world" twice`,
+		`I say 'hello   world'   `:     `I say 'hello   world'`,
+		`an "unterminated   quote`:     `an "unterminated   quote`,
+	}
+
+	for text, expected := range testCases {
+		if normalized := normalizeWhitespace(text); normalized != expected {
+			t.Errorf("expected %q but %q given", expected, normalized)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package gobdd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// StepTest is the subset of testing.TB which is available in step functions.
+//
+// A step function receives it when its first parameter is a gobdd.StepTest:
+//
+//	func myStepFunction(t gobdd.StepTest, ctx context.Context, first int) {
+//	}
+type StepTest interface {
+	Log(args ...interface{})
+	Logf(format string, args ...interface{})
+	Error(args ...interface{})
+	Errorf(format string, args ...interface{})
+	Fail()
+	FailNow()
+	Fatal(args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+// errFailNow stops the execution of a step after FailNow, Fatal or Fatalf were called
+var errFailNow = errors.New("the step failed")
+
+// errPending stops the execution of a step after Pending was called
+var errPending = errors.New("the step is pending")
+
+// Pending marks the step as not implemented yet and stops its execution.
+// Pending steps are reported with their own status and fail the run only when the suite is run with WithStrict
+func Pending() {
+	panic(errPending)
+}
+
+// stepTest collects failures of a single step,
+// so they can be reported once together with the step's location
+type stepTest struct {
+	t StepTest
+
+	mu      sync.Mutex
+	failed  bool
+	pending bool
+	errors  []string
+	stack   []byte
+	args    []reflect.Value
+}
+
+func newStepTest(t StepTest) *stepTest {
+	return &stepTest{t: t}
+}
+
+func (st *stepTest) Log(args ...interface{}) {
+	st.t.Log(args...)
+}
+
+func (st *stepTest) Logf(format string, args ...interface{}) {
+	st.t.Logf(format, args...)
+}
+
+func (st *stepTest) Error(args ...interface{}) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.failed = true
+	st.errors = append(st.errors, fmt.Sprint(args...))
+}
+
+func (st *stepTest) Errorf(format string, args ...interface{}) {
+	st.Error(fmt.Sprintf(format, args...))
+}
+
+func (st *stepTest) Fail() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.failed = true
+}
+
+func (st *stepTest) FailNow() {
+	st.Fail()
+	panic(errFailNow)
+}
+
+func (st *stepTest) Fatal(args ...interface{}) {
+	st.Error(args...)
+	panic(errFailNow)
+}
+
+func (st *stepTest) Fatalf(format string, args ...interface{}) {
+	st.Errorf(format, args...)
+	panic(errFailNow)
+}
+
+// Failed tells whether the step failed
+func (st *stepTest) Failed() bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	return st.failed
+}
+
+// markPending records that the step called Pending
+func (st *stepTest) markPending() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.pending = true
+}
+
+// Pending tells whether the step called Pending
+func (st *stepTest) Pending() bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	return st.pending
+}
+
+// Errors returns all errors reported by the step
+func (st *stepTest) Errors() []string {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	return st.errors
+}
+
+// recordStack keeps the stack trace of the panicking step
+func (st *stepTest) recordStack(stack []byte) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.stack = stack
+}
+
+// Stack returns the stack trace of the step if it panicked
+func (st *stepTest) Stack() []byte {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	return st.stack
+}
+
+// recordArgs keeps arguments the step function was called with, not counting StepTest and the context
+func (st *stepTest) recordArgs(args []reflect.Value) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.args = args
+}
+
+// Args returns arguments the step function was called with
+func (st *stepTest) Args() []reflect.Value {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	return st.args
+}
+
+// panicError is the failure of a panicking step
+type panicError struct {
+	msg   string
+	stack []byte
+}
+
+func (e *panicError) Error() string {
+	return e.msg
+}
+
+// stdTest reports to the output of the suite when the suite isn't run with testing.T
+type stdTest struct {
+	w io.Writer
+}
+
+func (t stdTest) Log(args ...interface{}) {
+	fmt.Fprintln(t.w, args...)
+}
+
+func (t stdTest) Logf(format string, args ...interface{}) {
+	fmt.Fprintf(t.w, format+"\n", args...)
+}
+
+func (t stdTest) Error(args ...interface{}) {
+	t.Log(args...)
+}
+
+func (t stdTest) Errorf(format string, args ...interface{}) {
+	t.Logf(format, args...)
+}
+
+func (stdTest) Fail() {}
+
+func (stdTest) FailNow() {}
+
+func (t stdTest) Fatal(args ...interface{}) {
+	t.Log(args...)
+}
+
+func (t stdTest) Fatalf(format string, args ...interface{}) {
+	t.Logf(format, args...)
+}
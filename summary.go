@@ -0,0 +1,182 @@
+package gobdd
+
+import (
+	"sort"
+	"time"
+
+	"github.com/go-bdd/gobdd/models"
+)
+
+// Summary holds the results of a run
+type Summary struct {
+	// Features is the number of executed features
+	Features  int
+	Scenarios ResultCounts
+	Steps     ResultCounts
+	// Duration is how long the run took, including hooks and parsing of features
+	Duration time.Duration
+	// StepsDuration is the total duration of all executed steps
+	StepsDuration time.Duration
+	// FeatureDurations and ScenarioDurations hold how long executed features and scenarios took,
+	// from the start of their first step to the end of their last step, in the order they were executed
+	FeatureDurations  []Timing
+	ScenarioDurations []Timing
+
+	strict bool
+}
+
+// ResultCounts holds the number of scenarios or steps by their results
+type ResultCounts struct {
+	Passed    int
+	Failed    int
+	Skipped   int
+	Undefined int
+	Pending   int
+}
+
+// Total returns the number of all scenarios or steps
+func (c ResultCounts) Total() int {
+	return c.Passed + c.Failed + c.Skipped + c.Undefined + c.Pending
+}
+
+// Succeeded tells whether none of the executed scenarios failed or had undefined steps.
+// When the suite is run with WithStrict, scenarios with pending steps are treated as failed too
+func (s Summary) Succeeded() bool {
+	if s.strict && s.Scenarios.Pending > 0 {
+		return false
+	}
+
+	return s.Scenarios.Failed == 0 && s.Scenarios.Undefined == 0
+}
+
+// Timing is the duration of a feature or a scenario
+type Timing struct {
+	Name string
+	// Uri is the path of the feature file
+	Uri string
+	// Line is the line where the feature or the scenario starts
+	Line     int64
+	Duration time.Duration
+}
+
+// Slowest returns at most n scenarios which took the longest time, the slowest first.
+// It returns no scenarios when n isn't positive
+func (s Summary) Slowest(n int) []Timing {
+	if n <= 0 {
+		return []Timing{}
+	}
+
+	slowest := append([]Timing{}, s.ScenarioDurations...)
+	sort.SliceStable(slowest, func(i, j int) bool {
+		return slowest[i].Duration > slowest[j].Duration
+	})
+
+	if n < len(slowest) {
+		slowest = slowest[:n]
+	}
+
+	return slowest
+}
+
+// CollectedScenario is the result of an executed scenario
+type CollectedScenario struct {
+	// Feature is the name of the feature of the scenario
+	Feature string
+	Name    string
+	// Description is the free text below the name of the scenario, without indentation
+	Description string
+	// Tags are names of tags of the scenario, including the @ prefix
+	Tags   []string
+	Result models.Result
+	// Attempts is how many times the scenario was run when the suite retries failed scenarios
+	Attempts int
+	Steps    []CollectedStep
+}
+
+// CollectedStep is the result of an executed step of a scenario, including steps of the background
+type CollectedStep struct {
+	// Text is the text of the step without its keyword
+	Text   string
+	Result models.Result
+	// Err is the reason why the step failed, if it did
+	Err error
+}
+
+func collectScenarios(features []*models.Feature) []CollectedScenario {
+	results := []CollectedScenario{}
+
+	for _, feature := range features {
+		for _, scenario := range feature.Scenarios {
+			result := CollectedScenario{
+				Feature:     feature.Name,
+				Name:        scenario.Name,
+				Description: description(scenario.Description),
+				Tags:        make([]string, 0, len(scenario.Tags)),
+				Result:      scenario.Result(),
+				Attempts:    scenario.Attempts,
+				Steps:       make([]CollectedStep, 0, len(scenario.Steps)),
+			}
+
+			for _, tag := range scenario.Tags {
+				result.Tags = append(result.Tags, tag.Name)
+			}
+
+			for _, step := range scenario.Steps {
+				result.Steps = append(result.Steps, CollectedStep{
+					Text:   step.Text,
+					Result: step.Execution.Result,
+					Err:    step.Execution.Err,
+				})
+			}
+
+			results = append(results, result)
+		}
+	}
+
+	return results
+}
+
+func newSummary(features []*models.Feature, strict bool) Summary {
+	scenarios, steps := countResults(features)
+
+	summary := Summary{
+		Features:  len(features),
+		Scenarios: newResultCounts(scenarios),
+		Steps:     newResultCounts(steps),
+		strict:    strict,
+	}
+
+	for _, feature := range features {
+		summary.FeatureDurations = append(summary.FeatureDurations, Timing{
+			Name:     feature.Name,
+			Uri:      feature.Uri,
+			Line:     line(feature.Location),
+			Duration: feature.Duration(),
+		})
+
+		for _, scenario := range feature.Scenarios {
+			summary.ScenarioDurations = append(summary.ScenarioDurations, Timing{
+				Name:     scenario.Name,
+				Uri:      feature.Uri,
+				Line:     line(scenario.Location),
+				Duration: scenario.Duration(),
+			})
+
+			for _, step := range scenario.Steps {
+				summary.StepsDuration += step.Execution.Duration()
+			}
+		}
+	}
+
+	return summary
+}
+
+func newResultCounts(counts map[models.Result]int) ResultCounts {
+	return ResultCounts{
+		Passed:    counts[models.Passed],
+		Failed:    counts[models.Failed],
+		Skipped:   counts[models.Skipped],
+		Undefined: counts[models.Undefined],
+		Pending:   counts[models.Pending],
+	}
+}
@@ -0,0 +1,240 @@
+package gobdd
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	msgs "github.com/cucumber/messages/go/v21"
+)
+
+// Table holds the data table attached to a step.
+//
+// A step function receives the table when its last parameter is a *gobdd.Table:
+//
+//	func myStepFunction(ctx context.Context, table *gobdd.Table) {
+//	}
+//
+// The first row of the table is treated as the header.
+type Table struct {
+	rows [][]string
+}
+
+func newTable(dt *msgs.DataTable) *Table {
+	t := &Table{rows: [][]string{}}
+	if dt == nil {
+		return t
+	}
+
+	for _, row := range dt.Rows {
+		cells := make([]string, 0, len(row.Cells))
+		for _, cell := range row.Cells {
+			cells = append(cells, cell.Value)
+		}
+
+		t.rows = append(t.rows, cells)
+	}
+
+	return t
+}
+
+// Raw returns all rows of the table, including the header
+func (t *Table) Raw() [][]string {
+	return t.rows
+}
+
+// Header returns the first row of the table
+func (t *Table) Header() []string {
+	if len(t.rows) == 0 {
+		return []string{}
+	}
+
+	return t.rows[0]
+}
+
+// Rows returns all rows of the table except the header
+func (t *Table) Rows() [][]string {
+	if len(t.rows) < 2 {
+		return [][]string{}
+	}
+
+	return t.rows[1:]
+}
+
+// Maps returns all rows of the table except the header as maps where keys are the header's cells
+func (t *Table) Maps() []map[string]string {
+	header := t.Header()
+	maps := make([]map[string]string, 0, len(t.Rows()))
+
+	for _, row := range t.Rows() {
+		m := make(map[string]string, len(header))
+		for i, key := range header {
+			if i < len(row) {
+				m[key] = row[i]
+			}
+		}
+
+		maps = append(maps, m)
+	}
+
+	return maps
+}
+
+// String returns the value of the cell in the column of the row.
+// Rows are counted from 0 without the header, so String(0, 0) is the first cell below the header
+func (t *Table) String(row, col int) (string, error) {
+	rows := t.Rows()
+	if row < 0 || row >= len(rows) || col < 0 || col >= len(rows[row]) {
+		return "", fmt.Errorf("the cell (%d, %d) is out of range of the table with %d rows", row, col, len(rows))
+	}
+
+	return rows[row][col], nil
+}
+
+// Int returns the value of the cell in the column of the row converted to an int
+func (t *Table) Int(row, col int) (int, error) {
+	value, err := t.String(row, col)
+	if err != nil {
+		return 0, err
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("the cell (%d, %d) isn't an int: %w", row, col, err)
+	}
+
+	return i, nil
+}
+
+// Float returns the value of the cell in the column of the row converted to a float64
+func (t *Table) Float(row, col int) (float64, error) {
+	value, err := t.String(row, col)
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("the cell (%d, %d) isn't a float: %w", row, col, err)
+	}
+
+	return f, nil
+}
+
+// Bool returns the value of the cell in the column of the row converted to a bool
+func (t *Table) Bool(row, col int) (bool, error) {
+	value, err := t.String(row, col)
+	if err != nil {
+		return false, err
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("the cell (%d, %d) isn't a bool: %w", row, col, err)
+	}
+
+	return b, nil
+}
+
+// ColumnByName returns values of the column with the name in the header, for all rows except the header.
+// Rows without a cell in the column have an empty value
+func (t *Table) ColumnByName(name string) ([]string, error) {
+	col := -1
+	for i, cell := range t.Header() {
+		if cell == name {
+			col = i
+			break
+		}
+	}
+
+	if col < 0 {
+		return nil, fmt.Errorf("the table has no column %s", name)
+	}
+
+	values := make([]string, 0, len(t.Rows()))
+	for _, row := range t.Rows() {
+		value := ""
+		if col < len(row) {
+			value = row[col]
+		}
+
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// Unmarshal fills dest with values of the table.
+//
+// When dest is a pointer to a slice of structs, every row except the header becomes an element of the slice.
+// When dest is a pointer to a struct, the table is treated as a vertical one
+// where the first cell of every row is the key and the second cell is the value.
+// Keys are matched with fields by the gobdd tag or by their names, ignoring the case.
+// Keys without a matching field are ignored.
+func (t *Table) Unmarshal(dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return errors.New("the destination should be a non-nil pointer")
+	}
+
+	v = v.Elem()
+
+	switch {
+	case v.Kind() == reflect.Struct:
+		for _, row := range t.rows {
+			if len(row) < 2 {
+				continue
+			}
+
+			if err := setField(v, row[0], row[1]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Struct:
+		rows := reflect.MakeSlice(v.Type(), 0, len(t.Rows()))
+		for _, row := range t.Maps() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			for key, value := range row {
+				if err := setField(elem, key, value); err != nil {
+					return err
+				}
+			}
+
+			rows = reflect.Append(rows, elem)
+		}
+
+		v.Set(rows)
+
+		return nil
+	}
+
+	return fmt.Errorf("the destination should be a pointer to a struct or a slice of structs, got %s", v.Type())
+}
+
+// setField sets the field of the struct matching the key to the value converted to the type of the field
+func setField(v reflect.Value, key, value string) error {
+	field, ok := namedField(v.Type(), key)
+	if !ok {
+		return nil
+	}
+
+	if !isSupportedParamType(field.Type) {
+		return fmt.Errorf("the field %s has unsupported type %s", field.Name, field.Type)
+	}
+
+	converted, err := paramType([]byte(value), field.Type)
+	if err != nil {
+		return fmt.Errorf("the field %s cannot be set: %s", field.Name, err)
+	}
+
+	if !converted.Type().AssignableTo(field.Type) {
+		return fmt.Errorf("the field %s has unsupported type %s", field.Name, field.Type)
+	}
+
+	v.FieldByIndex(field.Index).Set(converted)
+
+	return nil
+}
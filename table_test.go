@@ -0,0 +1,123 @@
+package gobdd
+
+import (
+	"testing"
+
+	msgs "github.com/cucumber/messages/go/v21"
+	"github.com/stretchr/testify/require"
+)
+
+func dataTable(rows ...[]string) *msgs.DataTable {
+	dt := &msgs.DataTable{}
+	for _, row := range rows {
+		cells := []*msgs.TableCell{}
+		for _, value := range row {
+			cells = append(cells, &msgs.TableCell{Value: value})
+		}
+
+		dt.Rows = append(dt.Rows, &msgs.TableRow{Cells: cells})
+	}
+
+	return dt
+}
+
+type person struct {
+	Name   string
+	Age    int
+	Height float64 `gobdd:"height in meters"`
+	Admin  bool
+}
+
+func TestTableTypedCells(t *testing.T) {
+	table := newTable(dataTable(
+		[]string{"name", "age", "height in meters", "admin"},
+		[]string{"John", "42", "1.5", "true"},
+		[]string{"Anna", "37", "1.75"},
+	))
+
+	name, err := table.String(1, 0)
+	require.NoError(t, err)
+	require.Equal(t, "Anna", name)
+
+	age, err := table.Int(0, 1)
+	require.NoError(t, err)
+	require.Equal(t, 42, age)
+
+	height, err := table.Float(1, 2)
+	require.NoError(t, err)
+	require.Equal(t, 1.75, height)
+
+	admin, err := table.Bool(0, 3)
+	require.NoError(t, err)
+	require.True(t, admin)
+
+	_, err = table.Int(0, 0)
+	require.EqualError(t, err, `the cell (0, 0) isn't an int: strconv.Atoi: parsing "John": invalid syntax`)
+
+	ages, err := table.ColumnByName("age")
+	require.NoError(t, err)
+	require.Equal(t, []string{"42", "37"}, ages)
+
+	admins, err := table.ColumnByName("admin")
+	require.NoError(t, err)
+	require.Equal(t, []string{"true", ""}, admins)
+}
+
+func TestTableCellsOutOfRange(t *testing.T) {
+	table := newTable(dataTable(
+		[]string{"name", "admin"},
+		[]string{"John", "true"},
+		[]string{"Anna"},
+	))
+
+	_, err := table.String(2, 0)
+	require.EqualError(t, err, "the cell (2, 0) is out of range of the table with 2 rows")
+
+	_, err = table.Bool(1, 1)
+	require.EqualError(t, err, "the cell (1, 1) is out of range of the table with 2 rows")
+
+	_, err = table.Int(-1, 0)
+	require.Error(t, err)
+
+	_, err = table.ColumnByName("age")
+	require.EqualError(t, err, "the table has no column age")
+}
+
+func TestTableUnmarshalRows(t *testing.T) {
+	table := newTable(dataTable(
+		[]string{"name", "age", "height in meters", "admin", "unknown"},
+		[]string{"John", "42", "1.5", "true", "x"},
+		[]string{"Anna", "37", "1.75", "false", "y"},
+	))
+
+	var people []person
+	require.NoError(t, table.Unmarshal(&people))
+
+	require.Equal(t, []person{
+		{Name: "John", Age: 42, Height: 1.5, Admin: true},
+		{Name: "Anna", Age: 37, Height: 1.75},
+	}, people)
+}
+
+func TestTableUnmarshalVertical(t *testing.T) {
+	table := newTable(dataTable(
+		[]string{"name", "John"},
+		[]string{"age", "42"},
+	))
+
+	var p person
+	require.NoError(t, table.Unmarshal(&p))
+
+	require.Equal(t, person{Name: "John", Age: 42}, p)
+}
+
+func TestTableUnmarshalInvalidDestination(t *testing.T) {
+	table := newTable(dataTable([]string{"name"}, []string{"John"}))
+
+	require.EqualError(t, table.Unmarshal(person{}), "the destination should be a non-nil pointer")
+	require.EqualError(t, table.Unmarshal(&[]string{}),
+		"the destination should be a pointer to a struct or a slice of structs, got []string")
+
+	var invalid []struct{ Name []int }
+	require.EqualError(t, table.Unmarshal(&invalid), "the field Name has unsupported type []int")
+}
@@ -0,0 +1,167 @@
+package gobdd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// tagExpression is a parsed Cucumber tag expression like `@smoke and not @slow`
+type tagExpression interface {
+	evaluate(tags []string) bool
+}
+
+type tagLiteral string
+
+func (e tagLiteral) evaluate(tags []string) bool {
+	return contains(tags, string(e))
+}
+
+type notExpression struct {
+	expr tagExpression
+}
+
+func (e notExpression) evaluate(tags []string) bool {
+	return !e.expr.evaluate(tags)
+}
+
+type andExpression struct {
+	left, right tagExpression
+}
+
+func (e andExpression) evaluate(tags []string) bool {
+	return e.left.evaluate(tags) && e.right.evaluate(tags)
+}
+
+type orExpression struct {
+	left, right tagExpression
+}
+
+func (e orExpression) evaluate(tags []string) bool {
+	return e.left.evaluate(tags) || e.right.evaluate(tags)
+}
+
+// parseTagExpression parses a tag expression.
+// The supported operators are (from the highest precedence) `not`, `and`, `or`
+// and parentheses can be used to group expressions.
+func parseTagExpression(expr string) (tagExpression, error) {
+	p := &tagExpressionParser{tokens: tokenizeTagExpression(expr)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("the tag expression %q is empty", expr)
+	}
+
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid tag expression %q: %w", expr, err)
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("invalid tag expression %q: unexpected %q", expr, p.tokens[p.pos])
+	}
+
+	return e, nil
+}
+
+func tokenizeTagExpression(expr string) []string {
+	expr = strings.ReplaceAll(expr, "(", " ( ")
+	expr = strings.ReplaceAll(expr, ")", " ) ")
+
+	return strings.Fields(expr)
+}
+
+type tagExpressionParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *tagExpressionParser) next() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+
+	return p.tokens[p.pos]
+}
+
+func (p *tagExpressionParser) parseOr() (tagExpression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.next() == "or" {
+		p.pos++
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = orExpression{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *tagExpressionParser) parseAnd() (tagExpression, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.next() == "and" {
+		p.pos++
+
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		left = andExpression{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *tagExpressionParser) parseNot() (tagExpression, error) {
+	if p.next() != "not" {
+		return p.parseOperand()
+	}
+
+	p.pos++
+
+	e, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	return notExpression{expr: e}, nil
+}
+
+func (p *tagExpressionParser) parseOperand() (tagExpression, error) {
+	token := p.next()
+
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of the expression")
+	case token == "(":
+		p.pos++
+
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+
+		p.pos++
+
+		return e, nil
+	case strings.HasPrefix(token, "@"):
+		p.pos++
+
+		return tagLiteral(token), nil
+	}
+
+	return nil, fmt.Errorf("unexpected %q", token)
+}
@@ -0,0 +1,48 @@
+package gobdd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagExpression(t *testing.T) {
+	testCases := []struct {
+		expr     string
+		tags     []string
+		expected bool
+	}{
+		{expr: "@a", tags: []string{"@a"}, expected: true},
+		{expr: "@a", tags: []string{"@b"}, expected: false},
+		{expr: "@a and @b", tags: []string{"@a", "@b"}, expected: true},
+		{expr: "@a and @b", tags: []string{"@a"}, expected: false},
+		{expr: "@a or @b", tags: []string{"@b"}, expected: true},
+		{expr: "@a or @b", tags: []string{"@c"}, expected: false},
+		{expr: "not @a", tags: []string{"@b"}, expected: true},
+		{expr: "not @a", tags: []string{"@a"}, expected: false},
+		{expr: "@a or @b and @c", tags: []string{"@a"}, expected: true},
+		{expr: "(@a or @b) and @c", tags: []string{"@a"}, expected: false},
+		{expr: "@smoke and (not @wip)", tags: []string{"@smoke"}, expected: true},
+		{expr: "@smoke and (not @wip)", tags: []string{"@smoke", "@wip"}, expected: false},
+		{expr: "not (@a or @b)", tags: []string{}, expected: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.expr, func(t *testing.T) {
+			expr, err := parseTagExpression(testCase.expr)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, expr.evaluate(testCase.tags))
+		})
+	}
+}
+
+func TestInvalidTagExpression(t *testing.T) {
+	testCases := []string{"", "@a and", "(@a or @b", "@a @b", "not", "a and @b", "@a)"}
+
+	for _, testCase := range testCases {
+		t.Run(testCase, func(t *testing.T) {
+			_, err := parseTagExpression(testCase)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package gobdd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-bdd/gobdd/models"
+)
+
+// teamCityEscaper escapes values of attributes of TeamCity service messages
+var teamCityEscaper = strings.NewReplacer(
+	"|", "||",
+	"'", "|'",
+	"\n", "|n",
+	"\r", "|r",
+	"[", "|[",
+	"]", "|]",
+	"\u0085", "|x",
+	"\u2028", "|l",
+	"\u2029", "|p",
+)
+
+// teamCityOutput writes TeamCity service messages: every feature is reported as a test suite
+// and every scenario as a test. Messages of a scenario carry its flow id, so TeamCity can tell apart messages
+// of scenarios running in parallel. All methods are no-ops on a nil output
+type teamCityOutput struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func newTeamCityOutput(w io.Writer) *teamCityOutput {
+	if w == nil {
+		return nil
+	}
+
+	return &teamCityOutput{w: w}
+}
+
+// message writes the service message with attributes given as name and value pairs
+func (o *teamCityOutput) message(name string, attrs ...string) {
+	var b strings.Builder
+	b.WriteString("##teamcity[" + name)
+
+	for i := 0; i+1 < len(attrs); i += 2 {
+		fmt.Fprintf(&b, " %s='%s'", attrs[i], teamCityEscaper.Replace(attrs[i+1]))
+	}
+
+	b.WriteString("]\n")
+
+	_, _ = io.WriteString(o.w, b.String())
+}
+
+func (o *teamCityOutput) suiteStarted(feature *models.Feature) {
+	if o == nil {
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.message("testSuiteStarted", "name", feature.Name)
+}
+
+func (o *teamCityOutput) suiteFinished(feature *models.Feature) {
+	if o == nil {
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.message("testSuiteFinished", "name", feature.Name)
+}
+
+func (o *teamCityOutput) testStarted(feature *models.Feature, scenario *models.Scenario) {
+	if o == nil {
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.message("testStarted", "name", scenario.Name, "flowId", flowID(feature, scenario))
+}
+
+// testFinished reports the result of the scenario, with the error of the failed or undefined step
+func (o *teamCityOutput) testFinished(feature *models.Feature, scenario *models.Scenario, duration time.Duration) {
+	if o == nil {
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	flow := flowID(feature, scenario)
+
+	switch scenario.Result() {
+	case models.Failed, models.Undefined:
+		for _, step := range scenario.Steps {
+			if result := step.Execution.Result; result == models.Failed || result == models.Undefined {
+				details := fmt.Sprintf("%s%s (line %d)", step.Keyword, step.Text, line(step.Location))
+				if len(step.Execution.Stack) > 0 {
+					details += "\n" + string(step.Execution.Stack)
+				}
+
+				o.message("testFailed", "name", scenario.Name, "message", errorMessage(step.Execution.Err),
+					"details", details, "flowId", flow)
+
+				break
+			}
+		}
+	case models.Skipped, models.Pending:
+		o.message("testIgnored", "name", scenario.Name, "flowId", flow)
+	}
+
+	o.message("testFinished", "name", scenario.Name, "duration", fmt.Sprint(duration.Milliseconds()), "flowId", flow)
+}
+
+// flowID identifies the scenario by its location, which is unique for every row of examples of an outline
+func flowID(feature *models.Feature, scenario *models.Scenario) string {
+	return fmt.Sprintf("%s:%d", feature.Uri, line(scenario.Location))
+}
@@ -0,0 +1,63 @@
+package gobdd
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTeamCityOutput(t *testing.T) {
+	output := &bytes.Buffer{}
+	suite := NewSuite(WithFeaturesPath("features/report.feature"), WithTeamCityOutput(output))
+	suite.AddStep(`the step passes`, pass)
+	suite.AddStep(`the step fails`, failure)
+
+	require.NoError(t, suite.Run())
+
+	messages := regexp.MustCompile(`duration='\d+'`).ReplaceAllString(output.String(), "duration='0'")
+	require.Equal(t, strings.Join([]string{
+		"##teamcity[testSuiteStarted name='reporting results']",
+		"##teamcity[testStarted name='the passing scenario' flowId='features/report.feature:2']",
+		"##teamcity[testFinished name='the passing scenario' duration='0' flowId='features/report.feature:2']",
+		"##teamcity[testStarted name='the failing scenario' flowId='features/report.feature:5']",
+		"##teamcity[testFailed name='the failing scenario' message='the step failed' " +
+			"details='Then the step fails (line 7)' flowId='features/report.feature:5']",
+		"##teamcity[testFinished name='the failing scenario' duration='0' flowId='features/report.feature:5']",
+		"##teamcity[testSuiteFinished name='reporting results']",
+		"",
+	}, "\n"), messages)
+}
+
+func TestTeamCityOutputInParallel(t *testing.T) {
+	output := &bytes.Buffer{}
+	suite := NewSuite(WithFeaturesPath("features/parallel.feature"), RunInParallel(), WithTeamCityOutput(output))
+	suite.AddStep(`.*`, pass)
+
+	require.NoError(t, suite.Run())
+
+	started := map[string]bool{}
+	for _, message := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		if strings.HasPrefix(message, "##teamcity[testSuite") {
+			continue
+		}
+
+		flow := regexp.MustCompile(` flowId='([^']+)'`).FindStringSubmatch(message)
+		require.NotNil(t, flow, "the message %s has no flow id", message)
+
+		if strings.HasPrefix(message, "##teamcity[testStarted ") {
+			require.False(t, started[flow[1]], "the flow id %s is used by more scenarios", flow[1])
+			started[flow[1]] = true
+		} else {
+			require.True(t, started[flow[1]], "the message %s belongs to a scenario which didn't start", message)
+		}
+	}
+
+	require.Greater(t, len(started), 1)
+}
+
+func TestTeamCityEscaper(t *testing.T) {
+	require.Equal(t, "it|'s |[a|] ||pipe|| |nnew line|r", teamCityEscaper.Replace("it's [a] |pipe| \nnew line\r"))
+}